@@ -28,6 +28,7 @@ import (
 
 var (
 	profile string
+	debug   bool
 )
 
 var rootCmd = &cobra.Command{
@@ -44,4 +45,5 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Use a specific profile from your credential file.")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Turn on debug logging.")
 }
@@ -25,11 +25,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
 	credscache "github.com/Aton-Kish/aws-credscache-go/sdkv1"
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/spf13/cobra"
@@ -51,7 +50,7 @@ var sdkv1CacheCmd = &cobra.Command{
 			return err
 		}
 
-		if err := sdkv1InjectFileCacheProvider(ctx, sess.Config); err != nil {
+		if err := sdkv1InjectFileCacheProvider(ctx, sess.Config, profile); err != nil {
 			return err
 		}
 
@@ -85,7 +84,7 @@ var sdkv1NocacheCmd = &cobra.Command{
 func sdkv1NewSessionConfig(ctx context.Context, profile string) (*session.Session, error) {
 	o := session.Options{
 		SharedConfigState:       session.SharedConfigEnable,
-		AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+		AssumeRoleTokenProvider: credscacheutil.DeferredMFATokenProvider,
 	}
 
 	if profile != "" {
@@ -95,16 +94,28 @@ func sdkv1NewSessionConfig(ctx context.Context, profile string) (*session.Sessio
 	return session.NewSessionWithOptions(o)
 }
 
-func sdkv1InjectFileCacheProvider(ctx context.Context, cfg *aws.Config) error {
+func sdkv1InjectFileCacheProvider(ctx context.Context, cfg *aws.Config, profile string) error {
+	dir, err := credscacheutil.DefaultFileCacheDir()
+	if err != nil {
+		return err
+	}
+
 	optFns := []func(o *credscache.FileCacheOptions){
 		func(o *credscache.FileCacheOptions) {
-			home, _ := os.UserHomeDir()
-			o.FileCacheDir = filepath.Join(home, ".aws/cli/cache")
+			o.FileCacheDir = dir
+			o.Profile = profile
 		},
 	}
-	_, err := credscache.InjectFileCacheProvider(cfg, optFns...)
+	result, err := credscache.InjectFileCacheProvider(cfg, optFns...)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if debug {
+		fmt.Fprintln(os.Stderr, result)
+	}
+
+	return nil
 }
 
 func sdkv1PrintCallerIdentity(ctx context.Context, client *sts.STS) error {
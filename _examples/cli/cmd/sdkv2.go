@@ -25,8 +25,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
 	credscache "github.com/Aton-Kish/aws-credscache-go/sdkv2"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -85,7 +85,7 @@ var sdkv2NocacheCmd = &cobra.Command{
 func sdkv2LoadConfig(ctx context.Context, profile string) (aws.Config, error) {
 	optFns := []func(o *config.LoadOptions) error{
 		config.WithAssumeRoleCredentialOptions(func(o *stscreds.AssumeRoleOptions) {
-			o.TokenProvider = stscreds.StdinTokenProvider
+			o.TokenProvider = credscacheutil.DeferredMFATokenProvider
 		}),
 	}
 
@@ -97,15 +97,26 @@ func sdkv2LoadConfig(ctx context.Context, profile string) (aws.Config, error) {
 }
 
 func sdkv2InjectFileCacheProvider(ctx context.Context, cfg *aws.Config) error {
+	dir, err := credscacheutil.DefaultFileCacheDir()
+	if err != nil {
+		return err
+	}
+
 	optFns := []func(o *credscache.FileCacheOptions){
 		func(o *credscache.FileCacheOptions) {
-			home, _ := os.UserHomeDir()
-			o.FileCacheDir = filepath.Join(home, ".aws/cli/cache")
+			o.FileCacheDir = dir
 		},
 	}
-	_, err := credscache.InjectFileCacheProvider(cfg, optFns...)
+	result, err := credscache.InjectFileCacheProvider(cfg, optFns...)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if debug {
+		fmt.Fprintln(os.Stderr, result)
+	}
+
+	return nil
 }
 
 func sdkv2PrintCallerIdentity(ctx context.Context, client *sts.Client) error {
@@ -1,6 +1,6 @@
 module github.com/Aton-Kish/aws-credscache-go/_examples/cli
 
-go 1.20
+go 1.21
 
 require (
 	github.com/Aton-Kish/aws-credscache-go v0.0.0-00010101000000-000000000000
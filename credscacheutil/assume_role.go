@@ -21,20 +21,19 @@
 package credscacheutil
 
 import (
-	"crypto/sha1"
-	"encoding/hex"
 	"fmt"
-	"sort"
-	"strings"
 	"time"
 )
 
 type AssumeRoleCacheKeyGenerator struct {
-	RoleARN         string
-	RoleSessionName string
-	ExternalID      *string
-	SerialNumber    *string
-	Duration        time.Duration
+	RoleARN             string
+	RoleSessionName     string
+	ExternalID          *string
+	SerialNumber        *string
+	Duration            time.Duration
+	Partition           string
+	STSEndpoint         string
+	SourceAccessKeyHash string
 }
 
 var _ interface {
@@ -43,39 +42,56 @@ var _ interface {
 } = &AssumeRoleCacheKeyGenerator{}
 
 func (g AssumeRoleCacheKeyGenerator) String() string {
-	o := []string{}
+	b := NewCacheKeyBuilder()
 
-	o = append(o, fmt.Sprintf(`"RoleArn": "%s"`, g.RoleARN))
+	b.AddString("RoleArn", g.RoleARN)
 
 	if g.RoleSessionName != "" {
-		o = append(o, fmt.Sprintf(`"RoleSessionName": "%s"`, g.RoleSessionName))
+		b.AddString("RoleSessionName", g.RoleSessionName)
 	}
 
 	if g.ExternalID != nil {
-		o = append(o, fmt.Sprintf(`"ExternalId": "%s"`, *g.ExternalID))
+		b.AddString("ExternalId", *g.ExternalID)
 	}
 
 	if g.SerialNumber != nil {
-		o = append(o, fmt.Sprintf(`"SerialNumber": "%s"`, *g.SerialNumber))
+		b.AddString("SerialNumber", *g.SerialNumber)
 	}
 
 	if g.Duration != 0 {
-		o = append(o, fmt.Sprintf(`"DurationSeconds": %d`, int(g.Duration.Seconds())))
+		b.AddInt("DurationSeconds", int64(g.Duration.Seconds()))
 	}
 
-	sort.Slice(o, func(i, j int) bool { return o[i] < o[j] })
+	// the AWS CLI keys on none of the following, so they are only set on request
+	if g.Partition != "" {
+		b.AddString("Partition", g.Partition)
+	}
+
+	if g.STSEndpoint != "" {
+		b.AddString("StsEndpoint", g.STSEndpoint)
+	}
+
+	if g.SourceAccessKeyHash != "" {
+		b.AddString("SourceAccessKeyHash", g.SourceAccessKeyHash)
+	}
 
-	return fmt.Sprintf("{%s}", strings.Join(o, ", "))
+	return b.String()
 }
 
 func (g *AssumeRoleCacheKeyGenerator) CacheKey() (string, error) {
-	hash := sha1.New()
-	if _, err := hash.Write([]byte(g.String())); err != nil {
-		err = fmt.Errorf("failed to write hash, %w", err)
-		return "", err
-	}
+	return sha1Hex(g.String())
+}
 
-	key := strings.ToLower(hex.EncodeToString(hash.Sum(nil)))
+// AssumeRoleFallbackDurations returns the requested duration followed by
+// every whole hour below it down to one hour, the lower bound of the
+// MaxSessionDuration of a role.
+func AssumeRoleFallbackDurations(requested time.Duration) []time.Duration {
+	durations := []time.Duration{requested}
+	for d := requested.Truncate(time.Hour); d >= time.Hour; d -= time.Hour {
+		if d < requested {
+			durations = append(durations, d)
+		}
+	}
 
-	return key, nil
+	return durations
 }
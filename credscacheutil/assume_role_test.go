@@ -214,6 +214,27 @@ func TestAssumeRoleCacheKeyGenerator_String(t *testing.T) {
 				res: `{"DurationSeconds": 3600, "ExternalId": "external_id", "RoleArn": "role_arn", "RoleSessionName": "role_session_name", "SerialNumber": "mfa_serial"}`,
 			},
 		},
+		{
+			name: "positive case: with RoleARN, Partition, STSEndpoint",
+			generator: AssumeRoleCacheKeyGenerator{
+				RoleARN:     "role_arn",
+				Partition:   "aws-us-gov",
+				STSEndpoint: "https://sts.us-gov-west-1.amazonaws.com",
+			},
+			expected: expected{
+				res: `{"Partition": "aws-us-gov", "RoleArn": "role_arn", "StsEndpoint": "https://sts.us-gov-west-1.amazonaws.com"}`,
+			},
+		},
+		{
+			name: "positive case: with RoleARN, SourceAccessKeyHash",
+			generator: AssumeRoleCacheKeyGenerator{
+				RoleARN:             "role_arn",
+				SourceAccessKeyHash: "25910f981e85ca04baf359199dd0bd4a3ae738b6",
+			},
+			expected: expected{
+				res: `{"RoleArn": "role_arn", "SourceAccessKeyHash": "25910f981e85ca04baf359199dd0bd4a3ae738b6"}`,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -428,6 +449,29 @@ func TestAssumeRoleCacheKeyGenerator_CacheKey(t *testing.T) {
 				err: nil,
 			},
 		},
+		{
+			name: "positive case: with RoleARN, Partition, STSEndpoint",
+			generator: AssumeRoleCacheKeyGenerator{
+				RoleARN:     "role_arn",
+				Partition:   "aws-us-gov",
+				STSEndpoint: "https://sts.us-gov-west-1.amazonaws.com",
+			},
+			expected: expected{
+				res: "6ada2148aec1c7d3434f4169f56ac4b97d7861ee",
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: with RoleARN, SourceAccessKeyHash",
+			generator: AssumeRoleCacheKeyGenerator{
+				RoleARN:             "role_arn",
+				SourceAccessKeyHash: "25910f981e85ca04baf359199dd0bd4a3ae738b6",
+			},
+			expected: expected{
+				res: "84147520036657de83aae79e4819aec600adb09b",
+				err: nil,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -444,3 +488,64 @@ func TestAssumeRoleCacheKeyGenerator_CacheKey(t *testing.T) {
 		})
 	}
 }
+
+func TestAssumeRoleFallbackDurations(t *testing.T) {
+	type args struct {
+		requested time.Duration
+	}
+
+	type expected struct {
+		res []time.Duration
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: whole hours",
+			args: args{
+				requested: time.Duration(4) * time.Hour,
+			},
+			expected: expected{
+				res: []time.Duration{time.Duration(4) * time.Hour, time.Duration(3) * time.Hour, time.Duration(2) * time.Hour, time.Duration(1) * time.Hour},
+			},
+		},
+		{
+			name: "positive case: fraction of an hour",
+			args: args{
+				requested: time.Duration(90) * time.Minute,
+			},
+			expected: expected{
+				res: []time.Duration{time.Duration(90) * time.Minute, time.Duration(1) * time.Hour},
+			},
+		},
+		{
+			name: "positive case: one hour or less",
+			args: args{
+				requested: time.Duration(30) * time.Minute,
+			},
+			expected: expected{
+				res: []time.Duration{time.Duration(30) * time.Minute},
+			},
+		},
+		{
+			name: "positive case: default duration",
+			args: args{
+				requested: 0,
+			},
+			expected: expected{
+				res: []time.Duration{0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := AssumeRoleFallbackDurations(tt.args.requested)
+
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
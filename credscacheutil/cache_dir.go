@@ -0,0 +1,81 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FileCacheDirEnvVar  = "AWS_CREDSCACHE_FILE_CACHE_DIR"
+	ConfigFileEnvVar    = "AWS_CONFIG_FILE"
+	defaultConfigDir    = ".aws"
+	defaultFileCacheDir = "cli/cache"
+)
+
+func DefaultFileCacheDir() (string, error) {
+	if dir := os.Getenv(FileCacheDirEnvVar); dir != "" {
+		return expandHome(dir)
+	}
+
+	configDir, err := defaultConfigDirPath()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, filepath.FromSlash(defaultFileCacheDir)), nil
+}
+
+func defaultConfigDirPath() (string, error) {
+	if file := os.Getenv(ConfigFileEnvVar); file != "" {
+		path, err := expandHome(file)
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.Dir(path), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		err = fmt.Errorf("failed to resolve home directory, %w", err)
+		return "", err
+	}
+
+	return filepath.Join(home, defaultConfigDir), nil
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		err = fmt.Errorf("failed to resolve home directory, %w", err)
+		return "", err
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultFileCacheDir(t *testing.T) {
+	type env struct {
+		home         string
+		fileCacheDir string
+		configFile   string
+	}
+
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		env      env
+		expected expected
+	}{
+		{
+			name: "positive case: home",
+			env: env{
+				home: "/home/gopher",
+			},
+			expected: expected{
+				res: "/home/gopher/.aws/cli/cache",
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: AWS_CONFIG_FILE",
+			env: env{
+				home:       "/home/gopher",
+				configFile: "/etc/aws/config",
+			},
+			expected: expected{
+				res: "/etc/aws/cli/cache",
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: AWS_CONFIG_FILE with tilde",
+			env: env{
+				home:       "/home/gopher",
+				configFile: "~/.config/aws/config",
+			},
+			expected: expected{
+				res: "/home/gopher/.config/aws/cli/cache",
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: AWS_CREDSCACHE_FILE_CACHE_DIR",
+			env: env{
+				home:         "/home/gopher",
+				fileCacheDir: "/var/cache/aws",
+				configFile:   "/etc/aws/config",
+			},
+			expected: expected{
+				res: "/var/cache/aws",
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: AWS_CREDSCACHE_FILE_CACHE_DIR with tilde",
+			env: env{
+				home:         "/home/gopher",
+				fileCacheDir: "~/.cache/aws",
+			},
+			expected: expected{
+				res: "/home/gopher/.cache/aws",
+				err: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("HOME", tt.env.home)
+			t.Setenv(FileCacheDirEnvVar, tt.env.fileCacheDir)
+			t.Setenv(ConfigFileEnvVar, tt.env.configFile)
+
+			// Act
+			actual, err := DefaultFileCacheDir()
+
+			// Assert
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, filepath.FromSlash(tt.expected.res), actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
@@ -20,6 +20,29 @@
 
 package credscacheutil
 
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
 type CacheKeyer interface {
 	CacheKey() (string, error)
 }
+
+func sha1Hex(s string) (string, error) {
+	hash := sha1.New()
+	if _, err := hash.Write([]byte(s)); err != nil {
+		err = fmt.Errorf("failed to write hash, %w", err)
+		return "", err
+	}
+
+	return strings.ToLower(hex.EncodeToString(hash.Sum(nil))), nil
+}
+
+// AccessKeyIDHash hashes an access key ID so that a cache key can be bound to
+// the caller without writing the access key ID itself anywhere.
+func AccessKeyIDHash(accessKeyID string) (string, error) {
+	return sha1Hex(accessKeyID)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+type CacheKeyBuilder struct {
+	attributes map[string]string
+}
+
+var _ interface {
+	fmt.Stringer
+	CacheKeyer
+} = &CacheKeyBuilder{}
+
+func NewCacheKeyBuilder() *CacheKeyBuilder {
+	return &CacheKeyBuilder{
+		attributes: map[string]string{},
+	}
+}
+
+func (b *CacheKeyBuilder) AddString(name string, value string) *CacheKeyBuilder {
+	b.attributes[name] = quoteJSONString(value)
+	return b
+}
+
+func (b *CacheKeyBuilder) AddInt(name string, value int64) *CacheKeyBuilder {
+	b.attributes[name] = strconv.FormatInt(value, 10)
+	return b
+}
+
+func (b *CacheKeyBuilder) AddBool(name string, value bool) *CacheKeyBuilder {
+	// Python encodes booleans in lower case as JSON does
+	b.attributes[name] = strconv.FormatBool(value)
+	return b
+}
+
+func (b *CacheKeyBuilder) String() string {
+	names := make([]string, 0, len(b.attributes))
+	for name := range b.attributes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	o := make([]string, 0, len(names))
+	for _, name := range names {
+		o = append(o, fmt.Sprintf("%s: %s", quoteJSONString(name), b.attributes[name]))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(o, ", "))
+}
+
+func (b *CacheKeyBuilder) CacheKey() (string, error) {
+	return sha1Hex(b.String())
+}
+
+// quoteJSONString quotes s in the same way as json.dumps of Python with the
+// default ensure_ascii, which the AWS CLI uses to compute cache keys.
+func quoteJSONString(s string) string {
+	sb := new(strings.Builder)
+	sb.WriteByte('"')
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			sb.WriteString(`\"`)
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r == '\b':
+			sb.WriteString(`\b`)
+		case r == '\f':
+			sb.WriteString(`\f`)
+		case r >= ' ' && r <= '~':
+			sb.WriteRune(r)
+		case r > 0xffff:
+			r1, r2 := utf16.EncodeRune(r)
+			fmt.Fprintf(sb, `\u%04x\u%04x`, r1, r2)
+		default:
+			fmt.Fprintf(sb, `\u%04x`, r)
+		}
+	}
+
+	sb.WriteByte('"')
+
+	return sb.String()
+}
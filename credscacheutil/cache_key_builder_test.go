@@ -0,0 +1,120 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheKeyBuilder_String(t *testing.T) {
+	type expected struct {
+		res string
+	}
+
+	tests := []struct {
+		name     string
+		builder  *CacheKeyBuilder
+		expected expected
+	}{
+		{
+			name:    "positive case: no attributes",
+			builder: NewCacheKeyBuilder(),
+			expected: expected{
+				res: `{}`,
+			},
+		},
+		{
+			name:    "positive case: sorted attributes",
+			builder: NewCacheKeyBuilder().AddString("RoleArn", "role_arn").AddInt("DurationSeconds", 3600).AddBool("Enabled", true),
+			expected: expected{
+				res: `{"DurationSeconds": 3600, "Enabled": true, "RoleArn": "role_arn"}`,
+			},
+		},
+		{
+			name:    "positive case: overwritten attribute",
+			builder: NewCacheKeyBuilder().AddString("RoleArn", "role_arn").AddString("RoleArn", "overwritten"),
+			expected: expected{
+				res: `{"RoleArn": "overwritten"}`,
+			},
+		},
+		{
+			name:    "positive case: escaped attribute",
+			builder: NewCacheKeyBuilder().AddString("Name", "say \"hi\"\\ \n\t\x01 é 日本 😀 \x7f"),
+			expected: expected{
+				res: `{"Name": "say \"hi\"\\ \n\t\u0001 \u00e9 \u65e5\u672c \ud83d\ude00 \u007f"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.builder.String()
+
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
+
+func TestCacheKeyBuilder_CacheKey(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		builder  *CacheKeyBuilder
+		expected expected
+	}{
+		{
+			name:    "positive case: compatible with AssumeRoleCacheKeyGenerator",
+			builder: NewCacheKeyBuilder().AddString("RoleArn", "role_arn"),
+			expected: expected{
+				res: "de1969e7a880d858c9bef3ba110acf78869d4527",
+				err: nil,
+			},
+		},
+		{
+			name:    "positive case: escaped attribute",
+			builder: NewCacheKeyBuilder().AddString("RoleArn", "arn").AddString("Name", "say \"hi\"\\ \n\t\x01 é 日本 😀 \x7f").AddInt("DurationSeconds", 3600).AddBool("Enabled", true),
+			expected: expected{
+				res: "fdb07df758b861c2fb77c7bce0f6f760f7108c7b",
+				err: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.builder.CacheKey()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
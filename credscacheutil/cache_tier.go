@@ -0,0 +1,86 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+type CacheTierPolicy int
+
+const (
+	CacheTierPolicyReadWrite CacheTierPolicy = iota
+	CacheTierPolicyReadOnly
+	CacheTierPolicyWriteOnly
+)
+
+func (p CacheTierPolicy) String() string {
+	switch p {
+	case CacheTierPolicyReadWrite:
+		return "ReadWrite"
+	case CacheTierPolicyReadOnly:
+		return "ReadOnly"
+	case CacheTierPolicyWriteOnly:
+		return "WriteOnly"
+	default:
+		return "Unknown"
+	}
+}
+
+func (p CacheTierPolicy) CanRead() bool {
+	return p == CacheTierPolicyReadWrite || p == CacheTierPolicyReadOnly
+}
+
+func (p CacheTierPolicy) CanWrite() bool {
+	return p == CacheTierPolicyReadWrite || p == CacheTierPolicyWriteOnly
+}
+
+type CacheTier struct {
+	Dir    string
+	Policy CacheTierPolicy
+}
+
+func ResolveCacheTiers(dir string, tiers []CacheTier) ([]CacheTier, error) {
+	if len(tiers) == 0 {
+		tiers = []CacheTier{{Dir: dir, Policy: CacheTierPolicyReadWrite}}
+	}
+
+	resolved := make([]CacheTier, 0, len(tiers))
+	for _, tier := range tiers {
+		if tier.Dir == "" {
+			defaultDir, err := DefaultFileCacheDir()
+			if err != nil {
+				return nil, err
+			}
+			tier.Dir = defaultDir
+		}
+
+		resolved = append(resolved, tier)
+	}
+
+	return resolved, nil
+}
+
+func PrimaryCacheTier(tiers []CacheTier) (CacheTier, bool) {
+	for _, tier := range tiers {
+		if tier.Policy.CanWrite() {
+			return tier, true
+		}
+	}
+
+	return CacheTier{}, false
+}
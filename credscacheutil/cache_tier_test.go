@@ -0,0 +1,185 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheTierPolicy(t *testing.T) {
+	type expected struct {
+		str      string
+		canRead  bool
+		canWrite bool
+	}
+
+	tests := []struct {
+		name     string
+		policy   CacheTierPolicy
+		expected expected
+	}{
+		{
+			name:   "positive case: ReadWrite",
+			policy: CacheTierPolicyReadWrite,
+			expected: expected{
+				str:      "ReadWrite",
+				canRead:  true,
+				canWrite: true,
+			},
+		},
+		{
+			name:   "positive case: ReadOnly",
+			policy: CacheTierPolicyReadOnly,
+			expected: expected{
+				str:      "ReadOnly",
+				canRead:  true,
+				canWrite: false,
+			},
+		},
+		{
+			name:   "positive case: WriteOnly",
+			policy: CacheTierPolicyWriteOnly,
+			expected: expected{
+				str:      "WriteOnly",
+				canRead:  false,
+				canWrite: true,
+			},
+		},
+		{
+			name:   "positive case: unknown",
+			policy: CacheTierPolicy(-1),
+			expected: expected{
+				str:      "Unknown",
+				canRead:  false,
+				canWrite: false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tt.expected.str, tt.policy.String())
+			assert.Equal(t, tt.expected.canRead, tt.policy.CanRead())
+			assert.Equal(t, tt.expected.canWrite, tt.policy.CanWrite())
+		})
+	}
+}
+
+func TestResolveCacheTiers(t *testing.T) {
+	type args struct {
+		dir   string
+		tiers []CacheTier
+	}
+
+	type expected struct {
+		res     []CacheTier
+		primary CacheTier
+		ok      bool
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: default directory",
+			args: args{
+				dir:   "",
+				tiers: nil,
+			},
+			expected: expected{
+				res:     []CacheTier{{Dir: "/home/gopher/.aws/cli/cache", Policy: CacheTierPolicyReadWrite}},
+				primary: CacheTier{Dir: "/home/gopher/.aws/cli/cache", Policy: CacheTierPolicyReadWrite},
+				ok:      true,
+			},
+		},
+		{
+			name: "positive case: specified directory",
+			args: args{
+				dir:   "/var/cache/aws",
+				tiers: nil,
+			},
+			expected: expected{
+				res:     []CacheTier{{Dir: "/var/cache/aws", Policy: CacheTierPolicyReadWrite}},
+				primary: CacheTier{Dir: "/var/cache/aws", Policy: CacheTierPolicyReadWrite},
+				ok:      true,
+			},
+		},
+		{
+			name: "positive case: tiers",
+			args: args{
+				dir: "/var/cache/aws",
+				tiers: []CacheTier{
+					{Dir: "/var/cache/team", Policy: CacheTierPolicyReadOnly},
+					{Dir: "", Policy: CacheTierPolicyReadWrite},
+				},
+			},
+			expected: expected{
+				res: []CacheTier{
+					{Dir: "/var/cache/team", Policy: CacheTierPolicyReadOnly},
+					{Dir: "/home/gopher/.aws/cli/cache", Policy: CacheTierPolicyReadWrite},
+				},
+				primary: CacheTier{Dir: "/home/gopher/.aws/cli/cache", Policy: CacheTierPolicyReadWrite},
+				ok:      true,
+			},
+		},
+		{
+			name: "positive case: read-only tiers",
+			args: args{
+				dir: "",
+				tiers: []CacheTier{
+					{Dir: "/var/cache/team", Policy: CacheTierPolicyReadOnly},
+				},
+			},
+			expected: expected{
+				res: []CacheTier{
+					{Dir: "/var/cache/team", Policy: CacheTierPolicyReadOnly},
+				},
+				primary: CacheTier{},
+				ok:      false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("HOME", "/home/gopher")
+			t.Setenv(FileCacheDirEnvVar, "")
+			t.Setenv(ConfigFileEnvVar, "")
+
+			// Act
+			actual, err := ResolveCacheTiers(tt.args.dir, tt.args.tiers)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.res, actual)
+
+			primary, ok := PrimaryCacheTier(actual)
+			assert.Equal(t, tt.expected.primary, primary)
+			assert.Equal(t, tt.expected.ok, ok)
+		})
+	}
+}
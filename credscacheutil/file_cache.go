@@ -22,7 +22,10 @@ package credscacheutil
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"time"
@@ -31,15 +34,20 @@ import (
 )
 
 type Loader interface {
-	Load(path string) error
+	Load(path string, optFns ...func(o *FileOptions)) error
 }
 
 type Storer interface {
-	Store(path string) error
+	Store(path string, optFns ...func(o *FileOptions)) error
+}
+
+type FileOptions struct {
+	InsecureSkipPermissionCheck bool
 }
 
 type FileCache struct {
-	Credentials CachedCredentials `json:"Credentials"`
+	Credentials              CachedCredentials `json:"Credentials"`
+	EffectiveDurationSeconds int64             `json:"EffectiveDurationSeconds,omitempty"`
 }
 
 type CachedCredentials struct {
@@ -54,10 +62,9 @@ var _ interface {
 	Storer
 } = &FileCache{}
 
-func (c *FileCache) Load(path string) error {
-	data, err := os.ReadFile(path)
+func (c *FileCache) Load(path string, optFns ...func(o *FileOptions)) error {
+	data, err := readFile(path, optFns...)
 	if err != nil {
-		err = fmt.Errorf("failed to read cache file, %w", err)
 		return err
 	}
 
@@ -72,25 +79,163 @@ func (c *FileCache) Load(path string) error {
 	return nil
 }
 
-func (c *FileCache) Store(path string) error {
+func (c *FileCache) Store(path string, optFns ...func(o *FileOptions)) error {
 	data, err := json.Marshal(c)
 	if err != nil {
 		err = fmt.Errorf("failed to encode cache json, %w", err)
 		return err
 	}
 
+	return writeFile(path, data, optFns...)
+}
+
+func RemoveFileCache(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		err = fmt.Errorf("failed to remove cache file, %w", err)
+		return err
+	}
+
+	return nil
+}
+
+func newFileOptions(optFns ...func(o *FileOptions)) FileOptions {
+	o := FileOptions{}
+
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	return o
+}
+
+func readFile(path string, optFns ...func(o *FileOptions)) ([]byte, error) {
+	o := newFileOptions(optFns...)
+
+	if o.InsecureSkipPermissionCheck {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			err = fmt.Errorf("failed to read cache file, %w", err)
+			return nil, err
+		}
+
+		return data, nil
+	}
+
+	if err := checkDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
+	f, err := openFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		err = fmt.Errorf("failed to read cache file, %w", err)
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func writeFile(path string, data []byte, optFns ...func(o *FileOptions)) error {
+	o := newFileOptions(optFns...)
+
 	dir := filepath.Dir(path)
 	if !xfilepath.Exists(dir) {
-		if err := os.MkdirAll(dir, 0755); err != nil {
+		if err := os.MkdirAll(dir, 0700); err != nil {
 			err = fmt.Errorf("failed to make directories, %w", err)
 			return err
 		}
 	}
 
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	if o.InsecureSkipPermissionCheck {
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			err = fmt.Errorf("failed to write cache file, %w", err)
+			return err
+		}
+
+		return nil
+	}
+
+	if err := checkDir(dir); err != nil {
+		return err
+	}
+
+	f, err := openFile(path, os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := f.Truncate(0); err != nil {
+		err = fmt.Errorf("failed to write cache file, %w", err)
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
 		err = fmt.Errorf("failed to write cache file, %w", err)
 		return err
 	}
 
 	return nil
 }
+
+func checkDir(dir string) error {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		err = fmt.Errorf("failed to stat cache directory, %w", err)
+		return err
+	}
+
+	if !fi.IsDir() {
+		return &InsecureCacheError{Path: dir, Reason: "not a directory"}
+	}
+
+	return checkOwnerAndMode(dir, fi)
+}
+
+func openFile(path string, flag int, perm fs.FileMode) (*os.File, error) {
+	f, err := xfilepath.OpenFileNoFollow(path, flag, perm)
+	if err != nil {
+		if errors.Is(err, xfilepath.ErrSymlink) {
+			return nil, &InsecureCacheError{Path: path, Reason: "symbolic link"}
+		}
+
+		err = fmt.Errorf("failed to open cache file, %w", err)
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		err = fmt.Errorf("failed to stat cache file, %w", err)
+		return nil, err
+	}
+
+	if !fi.Mode().IsRegular() {
+		f.Close()
+		return nil, &InsecureCacheError{Path: path, Reason: "not a regular file"}
+	}
+
+	if err := checkOwnerAndMode(path, fi); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func checkOwnerAndMode(path string, fi fs.FileInfo) error {
+	if !xfilepath.IsOwnedByCurrentUser(fi) {
+		return &InsecureCacheError{Path: path, Reason: "not owned by current user"}
+	}
+
+	if xfilepath.IsWritableByOthers(fi) {
+		return &InsecureCacheError{Path: path, Reason: "writable by group or others"}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	FileCacheGCMarkerName = ".credscache-gc"
+)
+
+var (
+	defaultFileCacheGCGracePeriod = time.Duration(24) * time.Hour
+	defaultFileCacheGCInterval    = time.Duration(24) * time.Hour
+)
+
+type FileCacheGCOptions struct {
+	GracePeriod                 time.Duration
+	Interval                    time.Duration
+	InsecureSkipPermissionCheck bool
+}
+
+func CollectFileCacheGarbage(dir string, optFns ...func(o *FileCacheGCOptions)) ([]string, error) {
+	o := FileCacheGCOptions{
+		GracePeriod: defaultFileCacheGCGracePeriod,
+		Interval:    defaultFileCacheGCInterval,
+	}
+
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	fileOptions := func(fo *FileOptions) {
+		fo.InsecureSkipPermissionCheck = o.InsecureSkipPermissionCheck
+	}
+
+	now := time.Now()
+
+	// the marker file rate-limits collection across processes sharing the directory
+	marker := filepath.Join(dir, FileCacheGCMarkerName)
+	if fi, err := os.Stat(marker); err == nil && now.Sub(fi.ModTime()) < o.Interval {
+		return nil, nil
+	}
+
+	if err := writeFile(marker, []byte(now.UTC().Format(time.RFC3339)), fileOptions); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		err = fmt.Errorf("failed to read cache directory, %w", err)
+		return nil, err
+	}
+
+	var removed []string
+	var errs []error
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		// entries that are unreadable or not credentials are left as they are
+		cache := new(FileCache)
+		if err := cache.Load(path, fileOptions); err != nil || cache.Credentials.Expires.IsZero() {
+			continue
+		}
+
+		if now.Before(cache.Credentials.Expires.Add(o.GracePeriod)) {
+			continue
+		}
+
+		if err := RemoveFileCache(path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		removed = append(removed, path)
+	}
+
+	return removed, errors.Join(errs...)
+}
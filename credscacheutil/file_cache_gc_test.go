@@ -0,0 +1,155 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectFileCacheGarbage(t *testing.T) {
+	expiresIn15Minutes := time.Now().UTC().Add(time.Duration(15) * time.Minute)
+	expired15MinutesAgo := time.Now().UTC().Add(-time.Duration(15) * time.Minute)
+	expired2DaysAgo := time.Now().UTC().Add(-time.Duration(48) * time.Hour)
+
+	type fields struct {
+		marker *time.Time
+	}
+
+	type args struct {
+		optFns []func(o *FileCacheGCOptions)
+	}
+
+	type expected struct {
+		removed       []string
+		markerUpdated bool
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: default grace period",
+			fields: fields{
+				marker: nil,
+			},
+			args: args{
+				optFns: []func(o *FileCacheGCOptions){},
+			},
+			expected: expected{
+				removed:       []string{"expired-2-days-ago.json"},
+				markerUpdated: true,
+			},
+		},
+		{
+			name: "positive case: no grace period",
+			fields: fields{
+				marker: nil,
+			},
+			args: args{
+				optFns: []func(o *FileCacheGCOptions){func(o *FileCacheGCOptions) { o.GracePeriod = 0 }},
+			},
+			expected: expected{
+				removed:       []string{"expired-15-minutes-ago.json", "expired-2-days-ago.json"},
+				markerUpdated: true,
+			},
+		},
+		{
+			name: "positive case: stale marker",
+			fields: fields{
+				marker: &expired2DaysAgo,
+			},
+			args: args{
+				optFns: []func(o *FileCacheGCOptions){},
+			},
+			expected: expected{
+				removed:       []string{"expired-2-days-ago.json"},
+				markerUpdated: true,
+			},
+		},
+		{
+			name: "positive case: rate-limited by marker",
+			fields: fields{
+				marker: &expired15MinutesAgo,
+			},
+			args: args{
+				optFns: []func(o *FileCacheGCOptions){},
+			},
+			expected: expected{
+				removed:       nil,
+				markerUpdated: false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tempDir := t.TempDir()
+			for name, expires := range map[string]time.Time{
+				"valid.json":                  expiresIn15Minutes,
+				"expired-15-minutes-ago.json": expired15MinutesAgo,
+				"expired-2-days-ago.json":     expired2DaysAgo,
+			} {
+				cache := &FileCache{Credentials: CachedCredentials{AccessKeyID: "AccessKeyID", Expires: expires}}
+				cache.Store(filepath.Join(tempDir, name))
+			}
+			os.WriteFile(filepath.Join(tempDir, "corrupt.json"), []byte("{"), 0600)
+			os.WriteFile(filepath.Join(tempDir, "other.txt"), []byte{}, 0600)
+
+			marker := filepath.Join(tempDir, FileCacheGCMarkerName)
+			if tt.fields.marker != nil {
+				os.WriteFile(marker, []byte{}, 0600)
+				os.Chtimes(marker, *tt.fields.marker, *tt.fields.marker)
+			}
+			start := time.Now().Add(-time.Second)
+
+			// Act
+			actual, err := CollectFileCacheGarbage(tempDir, tt.args.optFns...)
+
+			// Assert
+			assert.NoError(t, err)
+
+			var expected []string
+			for _, name := range tt.expected.removed {
+				expected = append(expected, filepath.Join(tempDir, name))
+			}
+			assert.Equal(t, expected, actual)
+
+			for _, name := range []string{"valid.json", "expired-15-minutes-ago.json", "expired-2-days-ago.json", "corrupt.json", "other.txt"} {
+				_, err := os.Stat(filepath.Join(tempDir, name))
+				assert.Equal(t, !slices.Contains(tt.expected.removed, name), err == nil, name)
+			}
+
+			fi, err := os.Stat(marker)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.markerUpdated, fi.ModTime().After(start))
+		})
+	}
+}
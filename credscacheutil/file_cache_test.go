@@ -21,6 +21,7 @@
 package credscacheutil
 
 import (
+	"os"
 	"path/filepath"
 	"syscall"
 	"testing"
@@ -166,3 +167,297 @@ func TestFileCache_Store(t *testing.T) {
 		})
 	}
 }
+
+func TestFileCache_LoadWithInsecurePath(t *testing.T) {
+	cache := &FileCache{
+		Credentials: CachedCredentials{
+			AccessKeyID:     "AccessKeyID",
+			SecretAccessKey: "SecretAccessKey",
+			SessionToken:    "SessionToken",
+			Expires:         time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+	}
+
+	secureDir := t.TempDir()
+	cache.Store(filepath.Join(secureDir, "cache.json"))
+	os.Symlink(filepath.Join(secureDir, "cache.json"), filepath.Join(secureDir, "symlink.json"))
+
+	insecureDir := t.TempDir()
+	cache.Store(filepath.Join(insecureDir, "cache.json"))
+	os.Chmod(insecureDir, 0777)
+
+	insecureFileDir := t.TempDir()
+	cache.Store(filepath.Join(insecureFileDir, "cache.json"))
+	os.Chmod(filepath.Join(insecureFileDir, "cache.json"), 0666)
+
+	type args struct {
+		path   string
+		optFns []func(o *FileOptions)
+	}
+
+	type expected struct {
+		cache *FileCache
+		err   *InsecureCacheError
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: insecure path with InsecureSkipPermissionCheck",
+			args: args{
+				path:   filepath.Join(insecureDir, "cache.json"),
+				optFns: []func(o *FileOptions){func(o *FileOptions) { o.InsecureSkipPermissionCheck = true }},
+			},
+			expected: expected{
+				cache: cache,
+				err:   nil,
+			},
+		},
+		{
+			name: "negative case: symbolic link",
+			args: args{
+				path:   filepath.Join(secureDir, "symlink.json"),
+				optFns: []func(o *FileOptions){},
+			},
+			expected: expected{
+				cache: nil,
+				err:   &InsecureCacheError{Path: filepath.Join(secureDir, "symlink.json"), Reason: "symbolic link"},
+			},
+		},
+		{
+			name: "negative case: world-writable directory",
+			args: args{
+				path:   filepath.Join(insecureDir, "cache.json"),
+				optFns: []func(o *FileOptions){},
+			},
+			expected: expected{
+				cache: nil,
+				err:   &InsecureCacheError{Path: insecureDir, Reason: "writable by group or others"},
+			},
+		},
+		{
+			name: "negative case: world-writable file",
+			args: args{
+				path:   filepath.Join(insecureFileDir, "cache.json"),
+				optFns: []func(o *FileOptions){},
+			},
+			expected: expected{
+				cache: nil,
+				err:   &InsecureCacheError{Path: filepath.Join(insecureFileDir, "cache.json"), Reason: "writable by group or others"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := new(FileCache)
+			err := actual.Load(tt.args.path, tt.args.optFns...)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.cache, actual)
+			} else {
+				var insecureCacheError *InsecureCacheError
+				assert.ErrorAs(t, err, &insecureCacheError)
+				assert.Equal(t, tt.expected.err, insecureCacheError)
+			}
+		})
+	}
+}
+
+func TestFileCache_StoreWithInsecurePath(t *testing.T) {
+	cache := &FileCache{
+		Credentials: CachedCredentials{
+			AccessKeyID:     "AccessKeyID",
+			SecretAccessKey: "SecretAccessKey",
+			SessionToken:    "SessionToken",
+			Expires:         time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+	}
+
+	secureDir := t.TempDir()
+	os.WriteFile(filepath.Join(secureDir, "target.json"), []byte{}, 0600)
+	os.Symlink(filepath.Join(secureDir, "target.json"), filepath.Join(secureDir, "symlink.json"))
+
+	insecureDir := t.TempDir()
+	os.Chmod(insecureDir, 0777)
+
+	type args struct {
+		path   string
+		optFns []func(o *FileOptions)
+	}
+
+	type expected struct {
+		err *InsecureCacheError
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: insecure path with InsecureSkipPermissionCheck",
+			args: args{
+				path:   filepath.Join(insecureDir, "cache.json"),
+				optFns: []func(o *FileOptions){func(o *FileOptions) { o.InsecureSkipPermissionCheck = true }},
+			},
+			expected: expected{
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: symbolic link",
+			args: args{
+				path:   filepath.Join(secureDir, "symlink.json"),
+				optFns: []func(o *FileOptions){},
+			},
+			expected: expected{
+				err: &InsecureCacheError{Path: filepath.Join(secureDir, "symlink.json"), Reason: "symbolic link"},
+			},
+		},
+		{
+			name: "negative case: world-writable directory",
+			args: args{
+				path:   filepath.Join(insecureDir, "cache.json"),
+				optFns: []func(o *FileOptions){},
+			},
+			expected: expected{
+				err: &InsecureCacheError{Path: insecureDir, Reason: "writable by group or others"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cache.Store(tt.args.path, tt.args.optFns...)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+			} else {
+				var insecureCacheError *InsecureCacheError
+				assert.ErrorAs(t, err, &insecureCacheError)
+				assert.Equal(t, tt.expected.err, insecureCacheError)
+			}
+		})
+	}
+}
+
+func TestFileCache_StorePermission(t *testing.T) {
+	cache := &FileCache{
+		Credentials: CachedCredentials{
+			AccessKeyID:     "AccessKeyID",
+			SecretAccessKey: "SecretAccessKey",
+			SessionToken:    "SessionToken",
+			Expires:         time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+	}
+
+	type expected struct {
+		dirPerm  os.FileMode
+		filePerm os.FileMode
+	}
+
+	tests := []struct {
+		name     string
+		expected expected
+	}{
+		{
+			name: "positive case: non-existing dir",
+			expected: expected{
+				dirPerm:  0700,
+				filePerm: 0600,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			dir := filepath.Join(t.TempDir(), "non-existing")
+			path := filepath.Join(dir, "cache.json")
+
+			// Act
+			err := cache.Store(path)
+
+			// Assert
+			assert.NoError(t, err)
+
+			dirInfo, _ := os.Stat(dir)
+			assert.Equal(t, tt.expected.dirPerm, dirInfo.Mode().Perm())
+
+			fileInfo, _ := os.Stat(path)
+			assert.Equal(t, tt.expected.filePerm, fileInfo.Mode().Perm())
+		})
+	}
+}
+
+func TestRemoveFileCache(t *testing.T) {
+	type args struct {
+		name string
+	}
+
+	type expected struct {
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: existing file",
+			args: args{
+				name: "cache.json",
+			},
+			expected: expected{
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: non-existing file",
+			args: args{
+				name: "non-existing.json",
+			},
+			expected: expected{
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: non-empty directory",
+			args: args{
+				name: "dir",
+			},
+			expected: expected{
+				err: syscall.ENOTEMPTY,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tempDir := t.TempDir()
+			os.WriteFile(filepath.Join(tempDir, "cache.json"), []byte("{}"), 0600)
+			os.MkdirAll(filepath.Join(tempDir, "dir", "child"), 0700)
+			path := filepath.Join(tempDir, tt.args.name)
+
+			// Act
+			err := RemoveFileCache(path)
+
+			// Assert
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				_, err := os.Stat(path)
+				assert.ErrorIs(t, err, os.ErrNotExist)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	MFATokenEnvVar  = "AWS_CREDSCACHE_MFA_TOKEN"
+	MFAPromptEnvVar = "AWS_CREDSCACHE_MFA_PROMPT"
+)
+
+const (
+	ttyPath = "/dev/tty"
+)
+
+// mfaPromptMu keeps prompts from several providers from interleaving.
+var mfaPromptMu sync.Mutex
+
+type MFAPrompt struct {
+	RoleARN      string
+	SerialNumber string
+	Profile      string
+	CachePath    string
+}
+
+var _ interface {
+	fmt.Stringer
+} = &MFAPrompt{}
+
+func (p MFAPrompt) String() string {
+	var details []string
+	if p.RoleARN != "" {
+		details = append(details, fmt.Sprintf("role %s", p.RoleARN))
+	}
+	if p.Profile != "" {
+		details = append(details, fmt.Sprintf("profile %s", p.Profile))
+	}
+	if p.CachePath != "" {
+		details = append(details, fmt.Sprintf("cache %s", p.CachePath))
+	}
+
+	s := "Enter MFA code"
+	if p.SerialNumber != "" {
+		s += fmt.Sprintf(" for %s", p.SerialNumber)
+	}
+	if len(details) > 0 {
+		s += fmt.Sprintf(" (%s)", strings.Join(details, ", "))
+	}
+
+	return s + ": "
+}
+
+type MFAPrompter func(prompt *MFAPrompt) (string, error)
+
+func NewMFATokenProvider(prompter MFAPrompter, prompt MFAPrompt) func() (string, error) {
+	if prompter == nil {
+		prompter = StdinMFAPrompter
+	}
+
+	return func() (string, error) {
+		mfaPromptMu.Lock()
+		defer mfaPromptMu.Unlock()
+
+		token, err := prompter(&prompt)
+		if err != nil {
+			err = fmt.Errorf("failed to prompt mfa token, %w", err)
+			return "", err
+		}
+
+		token = strings.TrimSpace(token)
+		if token == "" {
+			err := fmt.Errorf("%w, serial number %s", ErrEmptyMFAToken, prompt.SerialNumber)
+			return "", err
+		}
+
+		return token, nil
+	}
+}
+
+// DeferredMFATokenProvider satisfies the SDK check for a token provider when
+// the profile has mfa_serial. The injectors replace it with a prompt that
+// shows the role, profile and cache path; called directly it prompts on stdin.
+func DeferredMFATokenProvider() (string, error) {
+	return NewMFATokenProvider(StdinMFAPrompter, MFAPrompt{})()
+}
+
+func NeedsMFATokenProvider(serialNumber string, tokenProvider func() (string, error)) bool {
+	if serialNumber == "" {
+		return false
+	}
+
+	if tokenProvider == nil {
+		return true
+	}
+
+	return reflect.ValueOf(tokenProvider).Pointer() == reflect.ValueOf(DeferredMFATokenProvider).Pointer()
+}
+
+func NewReaderMFAPrompter(r io.Reader, w io.Writer) MFAPrompter {
+	return func(prompt *MFAPrompt) (string, error) {
+		if _, err := fmt.Fprint(w, prompt.String()); err != nil {
+			return "", err
+		}
+
+		line, err := bufio.NewReader(r).ReadString('\n')
+		if err != nil && !(err == io.EOF && line != "") {
+			return "", err
+		}
+
+		return strings.TrimSpace(line), nil
+	}
+}
+
+func StdinMFAPrompter(prompt *MFAPrompt) (string, error) {
+	return NewReaderMFAPrompter(os.Stdin, os.Stderr)(prompt)
+}
+
+// TTYMFAPrompter prompts on the controlling terminal, which keeps working
+// when stdin and stderr are redirected.
+func TTYMFAPrompter(prompt *MFAPrompt) (string, error) {
+	tty, err := os.OpenFile(ttyPath, os.O_RDWR, 0)
+	if err != nil {
+		return "", err
+	}
+	defer tty.Close()
+
+	return NewReaderMFAPrompter(tty, tty)(prompt)
+}
+
+// NewCommandMFAPrompter runs a command, typically a desktop dialog, and reads
+// the token from its stdout. The prompt is passed in AWS_CREDSCACHE_MFA_PROMPT.
+func NewCommandMFAPrompter(name string, args ...string) MFAPrompter {
+	return func(prompt *MFAPrompt) (string, error) {
+		var stdout bytes.Buffer
+		cmd := exec.Command(name, args...)
+		cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", MFAPromptEnvVar, prompt.String()))
+		cmd.Stdout = &stdout
+		cmd.Stderr = os.Stderr
+
+		if err := cmd.Run(); err != nil {
+			err = fmt.Errorf("failed to run mfa prompt command, %w", err)
+			return "", err
+		}
+
+		return strings.TrimSpace(stdout.String()), nil
+	}
+}
+
+// NewEnvMFAPrompter reads the token from an environment variable for
+// automation. An empty key means AWS_CREDSCACHE_MFA_TOKEN.
+func NewEnvMFAPrompter(key string) MFAPrompter {
+	if key == "" {
+		key = MFATokenEnvVar
+	}
+
+	return func(prompt *MFAPrompt) (string, error) {
+		return os.Getenv(key), nil
+	}
+}
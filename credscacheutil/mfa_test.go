@@ -0,0 +1,304 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMFAPrompt_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		prompt   MFAPrompt
+		expected string
+	}{
+		{
+			name: "positive case: full prompt",
+			prompt: MFAPrompt{
+				RoleARN:      "arn:aws:iam::123456789012:role/Admin",
+				SerialNumber: "arn:aws:iam::123456789012:mfa/user",
+				Profile:      "admin",
+				CachePath:    "/home/user/.aws/cli/cache/key.json",
+			},
+			expected: "Enter MFA code for arn:aws:iam::123456789012:mfa/user (role arn:aws:iam::123456789012:role/Admin, profile admin, cache /home/user/.aws/cli/cache/key.json): ",
+		},
+		{
+			name:     "positive case: empty prompt",
+			prompt:   MFAPrompt{},
+			expected: "Enter MFA code: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.prompt.String()
+
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestNewMFATokenProvider(t *testing.T) {
+	errPrompt := errors.New("failed to read")
+
+	type args struct {
+		prompter MFAPrompter
+	}
+
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: token",
+			args: args{
+				prompter: func(prompt *MFAPrompt) (string, error) { return " 123456\n", nil },
+			},
+			expected: expected{
+				res: "123456",
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: empty token",
+			args: args{
+				prompter: func(prompt *MFAPrompt) (string, error) { return "", nil },
+			},
+			expected: expected{
+				err: ErrEmptyMFAToken,
+			},
+		},
+		{
+			name: "negative case: prompter failure",
+			args: args{
+				prompter: func(prompt *MFAPrompt) (string, error) { return "", errPrompt },
+			},
+			expected: expected{
+				err: errPrompt,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, err := NewMFATokenProvider(tt.args.prompter, MFAPrompt{SerialNumber: "serial"})()
+
+			// Assert
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
+
+func TestNeedsMFATokenProvider(t *testing.T) {
+	type args struct {
+		serialNumber  string
+		tokenProvider func() (string, error)
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected bool
+	}{
+		{
+			name:     "positive case: unset token provider",
+			args:     args{serialNumber: "serial", tokenProvider: nil},
+			expected: true,
+		},
+		{
+			name:     "positive case: deferred token provider",
+			args:     args{serialNumber: "serial", tokenProvider: DeferredMFATokenProvider},
+			expected: true,
+		},
+		{
+			name:     "negative case: custom token provider",
+			args:     args{serialNumber: "serial", tokenProvider: func() (string, error) { return "123456", nil }},
+			expected: false,
+		},
+		{
+			name:     "negative case: no serial number",
+			args:     args{serialNumber: "", tokenProvider: nil},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := NeedsMFATokenProvider(tt.args.serialNumber, tt.args.tokenProvider)
+
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestNewReaderMFAPrompter(t *testing.T) {
+	type args struct {
+		input string
+	}
+
+	type expected struct {
+		res string
+		err bool
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name:     "positive case: line",
+			args:     args{input: "123456\n654321\n"},
+			expected: expected{res: "123456", err: false},
+		},
+		{
+			name:     "positive case: no trailing newline",
+			args:     args{input: "123456"},
+			expected: expected{res: "123456", err: false},
+		},
+		{
+			name:     "negative case: no input",
+			args:     args{input: ""},
+			expected: expected{err: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var w bytes.Buffer
+			prompt := &MFAPrompt{RoleARN: "arn:aws:iam::123456789012:role/Admin"}
+
+			// Act
+			actual, err := NewReaderMFAPrompter(strings.NewReader(tt.args.input), &w)(prompt)
+
+			// Assert
+			assert.Equal(t, prompt.String(), w.String())
+			if !tt.expected.err {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNewCommandMFAPrompter(t *testing.T) {
+	type args struct {
+		name string
+		args []string
+	}
+
+	type expected struct {
+		res string
+		err bool
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name:     "positive case: command output",
+			args:     args{name: "sh", args: []string{"-c", `case "$AWS_CREDSCACHE_MFA_PROMPT" in "Enter MFA code"*) echo 123456;; esac`}},
+			expected: expected{res: "123456", err: false},
+		},
+		{
+			name:     "negative case: command failure",
+			args:     args{name: "sh", args: []string{"-c", "exit 1"}},
+			expected: expected{err: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, err := NewCommandMFAPrompter(tt.args.name, tt.args.args...)(&MFAPrompt{})
+
+			// Assert
+			if !tt.expected.err {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNewEnvMFAPrompter(t *testing.T) {
+	type args struct {
+		key string
+		env map[string]string
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected string
+	}{
+		{
+			name:     "positive case: default key",
+			args:     args{key: "", env: map[string]string{MFATokenEnvVar: "123456"}},
+			expected: "123456",
+		},
+		{
+			name:     "positive case: custom key",
+			args:     args{key: "MY_MFA_TOKEN", env: map[string]string{"MY_MFA_TOKEN": "654321"}},
+			expected: "654321",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			for k, v := range tt.args.env {
+				t.Setenv(k, v)
+			}
+
+			// Act
+			actual, err := NewEnvMFAPrompter(tt.args.key)(&MFAPrompt{})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
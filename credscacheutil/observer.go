@@ -0,0 +1,105 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"context"
+	"time"
+)
+
+type EventKind int
+
+const (
+	EventKindCacheHit EventKind = iota + 1
+	EventKindCacheMiss
+	EventKindCacheExpired
+	EventKindRefreshStart
+	EventKindRefreshFinish
+	EventKindStoreFailure
+	EventKindCorruptEntry
+	EventKindGarbageCollect
+	EventKindInvalidate
+	EventKindInsecureEntry
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventKindCacheHit:
+		return "CacheHit"
+	case EventKindCacheMiss:
+		return "CacheMiss"
+	case EventKindCacheExpired:
+		return "CacheExpired"
+	case EventKindRefreshStart:
+		return "RefreshStart"
+	case EventKindRefreshFinish:
+		return "RefreshFinish"
+	case EventKindStoreFailure:
+		return "StoreFailure"
+	case EventKindCorruptEntry:
+		return "CorruptEntry"
+	case EventKindGarbageCollect:
+		return "GarbageCollect"
+	case EventKindInvalidate:
+		return "Invalidate"
+	case EventKindInsecureEntry:
+		return "InsecureEntry"
+	default:
+		return "Unknown"
+	}
+}
+
+type Event struct {
+	Kind     EventKind
+	CacheKey string
+	Path     string
+	Expires  time.Time
+	Latency  time.Duration
+	Err      error
+}
+
+type Observer interface {
+	Observe(ctx context.Context, event Event)
+}
+
+type ObserverFunc func(ctx context.Context, event Event)
+
+var _ interface {
+	Observer
+} = ObserverFunc(nil)
+
+func (f ObserverFunc) Observe(ctx context.Context, event Event) {
+	f(ctx, event)
+}
+
+type MultiObserver []Observer
+
+var _ interface {
+	Observer
+} = MultiObserver(nil)
+
+func (m MultiObserver) Observe(ctx context.Context, event Event) {
+	for _, o := range m {
+		if o != nil {
+			o.Observe(ctx, event)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"context"
+	"expvar"
+)
+
+const (
+	CounterCacheHits       = "cache_hits_total"
+	CounterCacheMisses     = "cache_misses_total"
+	CounterCacheExpired    = "cache_expired_total"
+	CounterRefreshes       = "refreshes_total"
+	CounterRefreshFailures = "refresh_failures_total"
+	CounterRefreshSeconds  = "refresh_seconds_total"
+	CounterStoreFailures   = "store_failures_total"
+	CounterCorruptEntries  = "corrupt_entries_total"
+	CounterInsecureEntries = "insecure_entries_total"
+)
+
+type CounterObserver struct {
+	counters *expvar.Map
+}
+
+var _ interface {
+	Observer
+} = &CounterObserver{}
+
+func NewCounterObserver() *CounterObserver {
+	counters := new(expvar.Map).Init()
+	for _, name := range []string{
+		CounterCacheHits,
+		CounterCacheMisses,
+		CounterCacheExpired,
+		CounterRefreshes,
+		CounterRefreshFailures,
+		CounterStoreFailures,
+		CounterCorruptEntries,
+		CounterInsecureEntries,
+	} {
+		counters.Set(name, new(expvar.Int))
+	}
+	counters.Set(CounterRefreshSeconds, new(expvar.Float))
+
+	return &CounterObserver{
+		counters: counters,
+	}
+}
+
+func (o *CounterObserver) Observe(ctx context.Context, event Event) {
+	switch event.Kind {
+	case EventKindCacheHit:
+		o.counters.Add(CounterCacheHits, 1)
+	case EventKindCacheMiss:
+		o.counters.Add(CounterCacheMisses, 1)
+	case EventKindCacheExpired:
+		o.counters.Add(CounterCacheExpired, 1)
+	case EventKindRefreshFinish:
+		o.counters.Add(CounterRefreshes, 1)
+		o.counters.AddFloat(CounterRefreshSeconds, event.Latency.Seconds())
+		if event.Err != nil {
+			o.counters.Add(CounterRefreshFailures, 1)
+		}
+	case EventKindStoreFailure:
+		o.counters.Add(CounterStoreFailures, 1)
+	case EventKindCorruptEntry:
+		o.counters.Add(CounterCorruptEntries, 1)
+	case EventKindInsecureEntry:
+		o.counters.Add(CounterInsecureEntries, 1)
+	}
+}
+
+func (o *CounterObserver) Counters() *expvar.Map {
+	return o.counters
+}
+
+func (o *CounterObserver) Publish(name string) {
+	expvar.Publish(name, o.counters)
+}
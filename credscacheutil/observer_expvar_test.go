@@ -0,0 +1,156 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterObserver_Observe(t *testing.T) {
+	type args struct {
+		events []Event
+	}
+
+	type expected struct {
+		res map[string]string
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: no events",
+			args: args{
+				events: []Event{},
+			},
+			expected: expected{
+				res: map[string]string{
+					CounterCacheHits:       "0",
+					CounterCacheMisses:     "0",
+					CounterCacheExpired:    "0",
+					CounterRefreshes:       "0",
+					CounterRefreshFailures: "0",
+					CounterRefreshSeconds:  "0",
+					CounterStoreFailures:   "0",
+					CounterCorruptEntries:  "0",
+					CounterInsecureEntries: "0",
+				},
+			},
+		},
+		{
+			name: "positive case: cache hits",
+			args: args{
+				events: []Event{
+					{Kind: EventKindCacheHit},
+					{Kind: EventKindCacheHit},
+				},
+			},
+			expected: expected{
+				res: map[string]string{
+					CounterCacheHits:       "2",
+					CounterCacheMisses:     "0",
+					CounterCacheExpired:    "0",
+					CounterRefreshes:       "0",
+					CounterRefreshFailures: "0",
+					CounterRefreshSeconds:  "0",
+					CounterStoreFailures:   "0",
+					CounterCorruptEntries:  "0",
+					CounterInsecureEntries: "0",
+				},
+			},
+		},
+		{
+			name: "positive case: refreshes",
+			args: args{
+				events: []Event{
+					{Kind: EventKindCacheMiss},
+					{Kind: EventKindRefreshStart},
+					{Kind: EventKindRefreshFinish, Latency: time.Duration(1500) * time.Millisecond},
+					{Kind: EventKindCacheExpired},
+					{Kind: EventKindRefreshStart},
+					{Kind: EventKindRefreshFinish, Latency: time.Duration(500) * time.Millisecond, Err: errors.New("failed to retrieve")},
+				},
+			},
+			expected: expected{
+				res: map[string]string{
+					CounterCacheHits:       "0",
+					CounterCacheMisses:     "1",
+					CounterCacheExpired:    "1",
+					CounterRefreshes:       "2",
+					CounterRefreshFailures: "1",
+					CounterRefreshSeconds:  "2",
+					CounterStoreFailures:   "0",
+					CounterCorruptEntries:  "0",
+					CounterInsecureEntries: "0",
+				},
+			},
+		},
+		{
+			name: "positive case: failures",
+			args: args{
+				events: []Event{
+					{Kind: EventKindStoreFailure},
+					{Kind: EventKindCorruptEntry},
+					{Kind: EventKindInsecureEntry},
+				},
+			},
+			expected: expected{
+				res: map[string]string{
+					CounterCacheHits:       "0",
+					CounterCacheMisses:     "0",
+					CounterCacheExpired:    "0",
+					CounterRefreshes:       "0",
+					CounterRefreshFailures: "0",
+					CounterRefreshSeconds:  "0",
+					CounterStoreFailures:   "1",
+					CounterCorruptEntries:  "1",
+					CounterInsecureEntries: "1",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			observer := NewCounterObserver()
+
+			// Act
+			for _, event := range tt.args.events {
+				observer.Observe(context.Background(), event)
+			}
+
+			// Assert
+			actual := map[string]string{}
+			for name := range tt.expected.res {
+				actual[name] = observer.Counters().Get(name).String()
+			}
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
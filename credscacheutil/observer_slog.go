@@ -0,0 +1,71 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"context"
+	"log/slog"
+)
+
+type SlogObserver struct {
+	logger *slog.Logger
+}
+
+var _ interface {
+	Observer
+} = &SlogObserver{}
+
+func NewSlogObserver(logger *slog.Logger) *SlogObserver {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SlogObserver{
+		logger: logger,
+	}
+}
+
+func (o *SlogObserver) Observe(ctx context.Context, event Event) {
+	level := slog.LevelDebug
+	if event.Err != nil || event.Kind == EventKindStoreFailure || event.Kind == EventKindCorruptEntry {
+		level = slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{
+		slog.String("event", event.Kind.String()),
+		slog.String("cache_key", event.CacheKey),
+		slog.String("path", event.Path),
+	}
+
+	if !event.Expires.IsZero() {
+		attrs = append(attrs, slog.Time("expires", event.Expires))
+	}
+
+	if event.Kind == EventKindRefreshFinish {
+		attrs = append(attrs, slog.Duration("latency", event.Latency))
+	}
+
+	if event.Err != nil {
+		attrs = append(attrs, slog.String("error", event.Err.Error()))
+	}
+
+	o.logger.LogAttrs(ctx, level, "credentials cache event", attrs...)
+}
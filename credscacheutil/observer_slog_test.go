@@ -0,0 +1,138 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogObserver_Observe(t *testing.T) {
+	type args struct {
+		event Event
+	}
+
+	type expected struct {
+		res map[string]interface{}
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: cache hit",
+			args: args{
+				event: Event{
+					Kind:     EventKindCacheHit,
+					CacheKey: "key",
+					Path:     "key.json",
+					Expires:  time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+				},
+			},
+			expected: expected{
+				res: map[string]interface{}{
+					"level":     "DEBUG",
+					"msg":       "credentials cache event",
+					"event":     "CacheHit",
+					"cache_key": "key",
+					"path":      "key.json",
+					"expires":   "2006-01-02T15:04:05Z",
+				},
+			},
+		},
+		{
+			name: "positive case: refresh finish",
+			args: args{
+				event: Event{
+					Kind:     EventKindRefreshFinish,
+					CacheKey: "key",
+					Path:     "key.json",
+					Latency:  time.Duration(3) * time.Second,
+				},
+			},
+			expected: expected{
+				res: map[string]interface{}{
+					"level":     "DEBUG",
+					"msg":       "credentials cache event",
+					"event":     "RefreshFinish",
+					"cache_key": "key",
+					"path":      "key.json",
+					"latency":   float64(3 * time.Second),
+				},
+			},
+		},
+		{
+			name: "positive case: store failure",
+			args: args{
+				event: Event{
+					Kind:     EventKindStoreFailure,
+					CacheKey: "key",
+					Path:     "key.json",
+					Err:      errors.New("read-only file system"),
+				},
+			},
+			expected: expected{
+				res: map[string]interface{}{
+					"level":     "WARN",
+					"msg":       "credentials cache event",
+					"event":     "StoreFailure",
+					"cache_key": "key",
+					"path":      "key.json",
+					"error":     "read-only file system",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			buf := new(bytes.Buffer)
+			logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+				Level: slog.LevelDebug,
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey && len(groups) == 0 {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			observer := NewSlogObserver(logger)
+
+			// Act
+			observer.Observe(context.Background(), tt.args.event)
+
+			// Assert
+			actual := map[string]interface{}{}
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &actual))
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventKind_String(t *testing.T) {
+	type expected struct {
+		res string
+	}
+
+	tests := []struct {
+		name     string
+		kind     EventKind
+		expected expected
+	}{
+		{
+			name: "positive case: CacheHit",
+			kind: EventKindCacheHit,
+			expected: expected{
+				res: "CacheHit",
+			},
+		},
+		{
+			name: "positive case: CacheMiss",
+			kind: EventKindCacheMiss,
+			expected: expected{
+				res: "CacheMiss",
+			},
+		},
+		{
+			name: "positive case: CacheExpired",
+			kind: EventKindCacheExpired,
+			expected: expected{
+				res: "CacheExpired",
+			},
+		},
+		{
+			name: "positive case: RefreshStart",
+			kind: EventKindRefreshStart,
+			expected: expected{
+				res: "RefreshStart",
+			},
+		},
+		{
+			name: "positive case: RefreshFinish",
+			kind: EventKindRefreshFinish,
+			expected: expected{
+				res: "RefreshFinish",
+			},
+		},
+		{
+			name: "positive case: StoreFailure",
+			kind: EventKindStoreFailure,
+			expected: expected{
+				res: "StoreFailure",
+			},
+		},
+		{
+			name: "positive case: CorruptEntry",
+			kind: EventKindCorruptEntry,
+			expected: expected{
+				res: "CorruptEntry",
+			},
+		},
+		{
+			name: "positive case: GarbageCollect",
+			kind: EventKindGarbageCollect,
+			expected: expected{
+				res: "GarbageCollect",
+			},
+		},
+		{
+			name: "positive case: Invalidate",
+			kind: EventKindInvalidate,
+			expected: expected{
+				res: "Invalidate",
+			},
+		},
+		{
+			name: "positive case: InsecureEntry",
+			kind: EventKindInsecureEntry,
+			expected: expected{
+				res: "InsecureEntry",
+			},
+		},
+		{
+			name: "positive case: unknown",
+			kind: EventKind(0),
+			expected: expected{
+				res: "Unknown",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.kind.String()
+
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
+
+func TestMultiObserver_Observe(t *testing.T) {
+	type expected struct {
+		res []EventKind
+	}
+
+	tests := []struct {
+		name      string
+		observers int
+		expected  expected
+	}{
+		{
+			name:      "positive case: no observers",
+			observers: 0,
+			expected: expected{
+				res: nil,
+			},
+		},
+		{
+			name:      "positive case: multiple observers",
+			observers: 2,
+			expected: expected{
+				res: []EventKind{EventKindCacheHit, EventKindCacheHit},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var actual []EventKind
+			observer := MultiObserver{nil}
+			for i := 0; i < tt.observers; i++ {
+				observer = append(observer, ObserverFunc(func(ctx context.Context, event Event) {
+					actual = append(actual, event.Kind)
+				}))
+			}
+
+			// Act
+			observer.Observe(context.Background(), Event{Kind: EventKindCacheHit})
+
+			// Assert
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
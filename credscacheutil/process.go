@@ -0,0 +1,51 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"fmt"
+)
+
+type ProcessCacheKeyGenerator struct {
+	Command string
+	Profile string
+}
+
+var _ interface {
+	fmt.Stringer
+	CacheKeyer
+} = &ProcessCacheKeyGenerator{}
+
+func (g ProcessCacheKeyGenerator) String() string {
+	b := NewCacheKeyBuilder()
+
+	b.AddString("Command", g.Command)
+
+	if g.Profile != "" {
+		b.AddString("Profile", g.Profile)
+	}
+
+	return b.String()
+}
+
+func (g *ProcessCacheKeyGenerator) CacheKey() (string, error) {
+	return sha1Hex(g.String())
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessCacheKeyGenerator_String(t *testing.T) {
+	type expected struct {
+		res string
+	}
+
+	tests := []struct {
+		name      string
+		generator ProcessCacheKeyGenerator
+		expected  expected
+	}{
+		{
+			name: "positive case: with Command",
+			generator: ProcessCacheKeyGenerator{
+				Command: "/usr/local/bin/credential-process",
+			},
+			expected: expected{
+				res: `{"Command": "/usr/local/bin/credential-process"}`,
+			},
+		},
+		{
+			name: "positive case: with Command, Profile",
+			generator: ProcessCacheKeyGenerator{
+				Command: "/usr/local/bin/credential-process",
+				Profile: "process",
+			},
+			expected: expected{
+				res: `{"Command": "/usr/local/bin/credential-process", "Profile": "process"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.generator.String()
+
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
+
+func TestProcessCacheKeyGenerator_CacheKey(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name      string
+		generator ProcessCacheKeyGenerator
+		expected  expected
+	}{
+		{
+			name: "positive case: with Command",
+			generator: ProcessCacheKeyGenerator{
+				Command: "/usr/local/bin/credential-process",
+			},
+			expected: expected{
+				res: "8fdf75a9b72888ff5501e70e9e5c15551bc1d136",
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: with Command, Profile",
+			generator: ProcessCacheKeyGenerator{
+				Command: "/usr/local/bin/credential-process",
+				Profile: "process",
+			},
+			expected: expected{
+				res: "aa8b242b8ad27cf49baeae1527042806d6806138",
+				err: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.generator.CacheKey()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
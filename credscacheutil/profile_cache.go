@@ -0,0 +1,144 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+type ProfileCacheKind string
+
+const (
+	ProfileCacheKindAssumeRole                ProfileCacheKind = "AssumeRole"
+	ProfileCacheKindAssumeRoleWithWebIdentity ProfileCacheKind = "AssumeRoleWithWebIdentity"
+	ProfileCacheKindSSO                       ProfileCacheKind = "SSO"
+	ProfileCacheKindProcess                   ProfileCacheKind = "Process"
+)
+
+type ProfileCache struct {
+	Profile  string
+	Kind     ProfileCacheKind
+	CacheKey string
+	Path     string
+
+	// AWSCLIOnly is set for web identity and SSO profiles, whose cache files
+	// are written by the AWS CLI but never by the injectors of this module.
+	AWSCLIOnly bool
+}
+
+type ProfileCacheOptions struct {
+	ConfigFile      string
+	CredentialsFile string
+	FileCacheDir    string
+}
+
+// ResolveProfileCache returns the cache key and path of a shared config
+// profile. Web identity and SSO profiles resolve to AWS CLI cache paths only,
+// which is reported by AWSCLIOnly.
+func ResolveProfileCache(profile string, optFns ...func(o *ProfileCacheOptions)) (*ProfileCache, error) {
+	o := ProfileCacheOptions{}
+
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	p, err := LoadSharedProfile(profile, func(so *SharedConfigOptions) {
+		so.ConfigFile = o.ConfigFile
+		so.CredentialsFile = o.CredentialsFile
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	kind, keyer, err := profileCacheKeyer(p)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keyer.CacheKey()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := o.FileCacheDir
+	if dir == "" {
+		dir, err = DefaultFileCacheDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c := &ProfileCache{
+		Profile:    p.Name,
+		Kind:       kind,
+		CacheKey:   key,
+		Path:       filepath.Join(dir, fmt.Sprintf("%s.json", key)),
+		AWSCLIOnly: kind == ProfileCacheKindAssumeRoleWithWebIdentity || kind == ProfileCacheKindSSO,
+	}
+
+	return c, nil
+}
+
+func profileCacheKeyer(p *SharedProfile) (ProfileCacheKind, CacheKeyer, error) {
+	switch {
+	case p.RoleARN != "" && (p.SourceProfileName != "" || p.CredentialSource != ""):
+		g := &AssumeRoleCacheKeyGenerator{
+			RoleARN:         p.RoleARN,
+			RoleSessionName: p.RoleSessionName,
+			ExternalID:      p.ExternalID,
+			SerialNumber:    p.MFASerial,
+		}
+
+		// the AWS CLI keys on duration_seconds as written in the profile
+		if p.DurationSeconds != nil {
+			g.Duration = *p.DurationSeconds
+		}
+
+		return ProfileCacheKindAssumeRole, g, nil
+	case p.RoleARN != "" && p.WebIdentityTokenFile != "":
+		g := &AssumeRoleCacheKeyGenerator{
+			RoleARN:         p.RoleARN,
+			RoleSessionName: p.RoleSessionName,
+		}
+
+		return ProfileCacheKindAssumeRoleWithWebIdentity, g, nil
+	case p.SSOAccountID != "" && p.SSORoleName != "" && p.SSOStartURL != "":
+		g := &SSOCredentialsCacheKeyGenerator{
+			StartURL:    p.SSOStartURL,
+			RoleName:    p.SSORoleName,
+			AccountID:   p.SSOAccountID,
+			SessionName: p.SSOSession,
+		}
+
+		return ProfileCacheKindSSO, g, nil
+	case p.CredentialProcess != "":
+		g := &ProcessCacheKeyGenerator{
+			Command: p.CredentialProcess,
+			Profile: p.Name,
+		}
+
+		return ProfileCacheKindProcess, g, nil
+	default:
+		err := fmt.Errorf("%w, %s", ErrUncacheableProfile, p.Name)
+		return "", nil, err
+	}
+}
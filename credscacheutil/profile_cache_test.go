@@ -0,0 +1,195 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveProfileCache(t *testing.T) {
+	configFile, credentialsFile := writeTestSharedConfig(t)
+	fileCacheDir := t.TempDir()
+
+	type args struct {
+		profile string
+	}
+
+	type expected struct {
+		res *ProfileCache
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: role",
+			args: args{
+				profile: "role",
+			},
+			expected: expected{
+				res: &ProfileCache{
+					Profile:  "role",
+					Kind:     ProfileCacheKindAssumeRole,
+					CacheKey: "1e094c3701e27daf212957a8cab8274d136017b4",
+					Path:     filepath.Join(fileCacheDir, "1e094c3701e27daf212957a8cab8274d136017b4.json"),
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: chained role",
+			args: args{
+				profile: "chained",
+			},
+			expected: expected{
+				res: &ProfileCache{
+					Profile:  "chained",
+					Kind:     ProfileCacheKindAssumeRole,
+					CacheKey: "7530d4733f8b5e1375c19aef27f842c2e6708b86",
+					Path:     filepath.Join(fileCacheDir, "7530d4733f8b5e1375c19aef27f842c2e6708b86.json"),
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: credential_source with duration_seconds of 15 minutes",
+			args: args{
+				profile: "short-duration",
+			},
+			expected: expected{
+				res: &ProfileCache{
+					Profile:  "short-duration",
+					Kind:     ProfileCacheKindAssumeRole,
+					CacheKey: "4755bf8ee55691539b6cc08d0025fcc5314e2bd0",
+					Path:     filepath.Join(fileCacheDir, "4755bf8ee55691539b6cc08d0025fcc5314e2bd0.json"),
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: web identity",
+			args: args{
+				profile: "web-identity",
+			},
+			expected: expected{
+				res: &ProfileCache{
+					Profile:    "web-identity",
+					Kind:       ProfileCacheKindAssumeRoleWithWebIdentity,
+					CacheKey:   "e39f3433c0f7af1d28f3d73743ff0a33b5cae8ed",
+					Path:       filepath.Join(fileCacheDir, "e39f3433c0f7af1d28f3d73743ff0a33b5cae8ed.json"),
+					AWSCLIOnly: true,
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: sso-session",
+			args: args{
+				profile: "sso",
+			},
+			expected: expected{
+				res: &ProfileCache{
+					Profile:    "sso",
+					Kind:       ProfileCacheKindSSO,
+					CacheKey:   "25c1c91ee279faf6fa315ce95ff986ccdd52b587",
+					Path:       filepath.Join(fileCacheDir, "25c1c91ee279faf6fa315ce95ff986ccdd52b587.json"),
+					AWSCLIOnly: true,
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: legacy sso",
+			args: args{
+				profile: "legacy-sso",
+			},
+			expected: expected{
+				res: &ProfileCache{
+					Profile:    "legacy-sso",
+					Kind:       ProfileCacheKindSSO,
+					CacheKey:   "dd6d574879f4e925fe793f9c637e83402905e93e",
+					Path:       filepath.Join(fileCacheDir, "dd6d574879f4e925fe793f9c637e83402905e93e.json"),
+					AWSCLIOnly: true,
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: credential process",
+			args: args{
+				profile: "process",
+			},
+			expected: expected{
+				res: &ProfileCache{
+					Profile:  "process",
+					Kind:     ProfileCacheKindProcess,
+					CacheKey: "aa8b242b8ad27cf49baeae1527042806d6806138",
+					Path:     filepath.Join(fileCacheDir, "aa8b242b8ad27cf49baeae1527042806d6806138.json"),
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: static credentials",
+			args: args{
+				profile: "default",
+			},
+			expected: expected{
+				res: nil,
+				err: ErrUncacheableProfile,
+			},
+		},
+		{
+			name: "negative case: profile not found",
+			args: args{
+				profile: "non-existing",
+			},
+			expected: expected{
+				res: nil,
+				err: ErrProfileNotFound,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ResolveProfileCache(tt.args.profile, func(o *ProfileCacheOptions) {
+				o.ConfigFile = configFile
+				o.CredentialsFile = credentialsFile
+				o.FileCacheDir = fileCacheDir
+			})
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
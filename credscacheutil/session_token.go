@@ -0,0 +1,65 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"fmt"
+	"time"
+)
+
+// SessionTokenCacheKeyGenerator builds the key from the GetSessionToken
+// parameters as the AWS CLI does for AssumeRole. The profile is always part
+// of the key, since a call without MFA has no parameters at all.
+type SessionTokenCacheKeyGenerator struct {
+	SerialNumber        *string
+	Duration            time.Duration
+	Profile             string
+	SourceAccessKeyHash string
+}
+
+var _ interface {
+	fmt.Stringer
+	CacheKeyer
+} = &SessionTokenCacheKeyGenerator{}
+
+func (g SessionTokenCacheKeyGenerator) String() string {
+	b := NewCacheKeyBuilder()
+
+	b.AddString("Profile", g.Profile)
+
+	if g.SerialNumber != nil {
+		b.AddString("SerialNumber", *g.SerialNumber)
+	}
+
+	if g.Duration != 0 {
+		b.AddInt("DurationSeconds", int64(g.Duration.Seconds()))
+	}
+
+	if g.SourceAccessKeyHash != "" {
+		b.AddString("SourceAccessKeyHash", g.SourceAccessKeyHash)
+	}
+
+	return b.String()
+}
+
+func (g *SessionTokenCacheKeyGenerator) CacheKey() (string, error) {
+	return sha1Hex(g.String())
+}
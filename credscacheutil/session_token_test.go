@@ -0,0 +1,162 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionTokenCacheKeyGenerator_String(t *testing.T) {
+	type expected struct {
+		res string
+	}
+
+	tests := []struct {
+		name      string
+		generator SessionTokenCacheKeyGenerator
+		expected  expected
+	}{
+		{
+			name: "positive case: with Profile",
+			generator: SessionTokenCacheKeyGenerator{
+				Profile: "user",
+			},
+			expected: expected{
+				res: `{"Profile": "user"}`,
+			},
+		},
+		{
+			name: "positive case: with Profile, SerialNumber",
+			generator: SessionTokenCacheKeyGenerator{
+				Profile:      "user",
+				SerialNumber: aws.String("arn:aws:iam::123456789012:mfa/user"),
+			},
+			expected: expected{
+				res: `{"Profile": "user", "SerialNumber": "arn:aws:iam::123456789012:mfa/user"}`,
+			},
+		},
+		{
+			name: "positive case: with Profile, SerialNumber, Duration",
+			generator: SessionTokenCacheKeyGenerator{
+				Profile:      "user",
+				SerialNumber: aws.String("arn:aws:iam::123456789012:mfa/user"),
+				Duration:     time.Duration(1) * time.Hour,
+			},
+			expected: expected{
+				res: `{"DurationSeconds": 3600, "Profile": "user", "SerialNumber": "arn:aws:iam::123456789012:mfa/user"}`,
+			},
+		},
+		{
+			name: "positive case: with Profile, SourceAccessKeyHash",
+			generator: SessionTokenCacheKeyGenerator{
+				Profile:             "user",
+				SourceAccessKeyHash: "25910f981e85ca04baf359199dd0bd4a3ae738b6",
+			},
+			expected: expected{
+				res: `{"Profile": "user", "SourceAccessKeyHash": "25910f981e85ca04baf359199dd0bd4a3ae738b6"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.generator.String()
+
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
+
+func TestSessionTokenCacheKeyGenerator_CacheKey(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name      string
+		generator SessionTokenCacheKeyGenerator
+		expected  expected
+	}{
+		{
+			name: "positive case: with Profile",
+			generator: SessionTokenCacheKeyGenerator{
+				Profile: "user",
+			},
+			expected: expected{
+				res: "2ccd3697faf1c82c5cccfd279c2bf99895fc8864",
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: with Profile, SerialNumber",
+			generator: SessionTokenCacheKeyGenerator{
+				Profile:      "user",
+				SerialNumber: aws.String("arn:aws:iam::123456789012:mfa/user"),
+			},
+			expected: expected{
+				res: "1b335dc492b374d28d8dac1c24fbc1f1f0ecb853",
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: with Profile, SerialNumber, Duration",
+			generator: SessionTokenCacheKeyGenerator{
+				Profile:      "user",
+				SerialNumber: aws.String("arn:aws:iam::123456789012:mfa/user"),
+				Duration:     time.Duration(1) * time.Hour,
+			},
+			expected: expected{
+				res: "10bfaab46a91f295b891bda424c7d3272c1aee34",
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: with Profile, SourceAccessKeyHash",
+			generator: SessionTokenCacheKeyGenerator{
+				Profile:             "user",
+				SourceAccessKeyHash: "25910f981e85ca04baf359199dd0bd4a3ae738b6",
+			},
+			expected: expected{
+				res: "80a16a9b0b491acd5ef964a978858265e39f2dc1",
+				err: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.generator.CacheKey()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aton-Kish/aws-credscache-go/internal/ini"
+)
+
+const (
+	ProfileEnvVar               = "AWS_PROFILE"
+	DefaultProfileEnvVar        = "AWS_DEFAULT_PROFILE"
+	SharedCredentialsFileEnvVar = "AWS_SHARED_CREDENTIALS_FILE"
+	DefaultProfileName          = "default"
+	defaultConfigFile           = "config"
+	defaultCredentialsFile      = "credentials"
+)
+
+type SharedConfigOptions struct {
+	ConfigFile      string
+	CredentialsFile string
+}
+
+type SharedProfile struct {
+	Name                 string
+	Region               string
+	HasStaticCredentials bool
+	RoleARN              string
+	SourceProfileName    string
+	SourceProfile        *SharedProfile
+	CredentialSource     string
+	CredentialProcess    string
+	ExternalID           *string
+	MFASerial            *string
+	DurationSeconds      *time.Duration
+	RoleSessionName      string
+	WebIdentityTokenFile string
+	SSOSession           string
+	SSOStartURL          string
+	SSORegion            string
+	SSOAccountID         string
+	SSORoleName          string
+}
+
+func ResolveProfileName(name string) string {
+	if name != "" {
+		return name
+	}
+
+	if name := os.Getenv(ProfileEnvVar); name != "" {
+		return name
+	}
+
+	if name := os.Getenv(DefaultProfileEnvVar); name != "" {
+		return name
+	}
+
+	return DefaultProfileName
+}
+
+func LoadSharedProfile(name string, optFns ...func(o *SharedConfigOptions)) (*SharedProfile, error) {
+	config, credentials, err := parseSharedConfigFiles(optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadSharedProfile(ResolveProfileName(name), config, credentials, map[string]bool{})
+}
+
+// LoadSharedProfiles loads every profile of the shared config and credentials
+// files sorted by name. Profiles that fail to load, such as those in a
+// source_profile cycle, are left out.
+func LoadSharedProfiles(optFns ...func(o *SharedConfigOptions)) ([]*SharedProfile, error) {
+	config, credentials, err := parseSharedConfigFiles(optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for section := range config {
+		if name, ok := strings.CutPrefix(section, "profile "); ok {
+			names[name] = true
+		} else if section == DefaultProfileName {
+			names[section] = true
+		}
+	}
+	for section := range credentials {
+		names[section] = true
+	}
+
+	profiles := make([]*SharedProfile, 0, len(names))
+	for name := range names {
+		p, err := loadSharedProfile(name, config, credentials, map[string]bool{})
+		if err != nil {
+			continue
+		}
+
+		profiles = append(profiles, p)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+
+	return profiles, nil
+}
+
+func parseSharedConfigFiles(optFns ...func(o *SharedConfigOptions)) (ini.Sections, ini.Sections, error) {
+	o := SharedConfigOptions{}
+
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	configFile, err := sharedConfigFilePath(o.ConfigFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credentialsFile, err := sharedCredentialsFilePath(o.CredentialsFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config, err := ini.ParseFile(configFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credentials, err := ini.ParseFile(credentialsFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return config, credentials, nil
+}
+
+func sharedConfigFilePath(path string) (string, error) {
+	if path != "" {
+		return expandHome(path)
+	}
+
+	if path := os.Getenv(ConfigFileEnvVar); path != "" {
+		return expandHome(path)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		err = fmt.Errorf("failed to resolve home directory, %w", err)
+		return "", err
+	}
+
+	return filepath.Join(home, defaultConfigDir, defaultConfigFile), nil
+}
+
+func sharedCredentialsFilePath(path string) (string, error) {
+	if path != "" {
+		return expandHome(path)
+	}
+
+	if path := os.Getenv(SharedCredentialsFileEnvVar); path != "" {
+		return expandHome(path)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		err = fmt.Errorf("failed to resolve home directory, %w", err)
+		return "", err
+	}
+
+	return filepath.Join(home, defaultConfigDir, defaultCredentialsFile), nil
+}
+
+func loadSharedProfile(name string, config ini.Sections, credentials ini.Sections, visited map[string]bool) (*SharedProfile, error) {
+	if visited[name] {
+		err := fmt.Errorf("%w, %s", ErrSourceProfileCycle, name)
+		return nil, err
+	}
+	visited[name] = true
+
+	section := ini.Section{}
+	found := false
+
+	configSection, ok := config["profile "+name]
+	if !ok && name == DefaultProfileName {
+		configSection, ok = config[DefaultProfileName]
+	}
+	if ok {
+		found = true
+		for k, v := range configSection {
+			section[k] = v
+		}
+	}
+
+	if credentialsSection, ok := credentials[name]; ok {
+		found = true
+		for k, v := range credentialsSection {
+			section[k] = v
+		}
+	}
+
+	if !found {
+		err := fmt.Errorf("%w, %s", ErrProfileNotFound, name)
+		return nil, err
+	}
+
+	p := &SharedProfile{
+		Name:                 name,
+		Region:               section["region"],
+		HasStaticCredentials: section["aws_access_key_id"] != "" && section["aws_secret_access_key"] != "",
+		RoleARN:              section["role_arn"],
+		SourceProfileName:    section["source_profile"],
+		CredentialSource:     section["credential_source"],
+		CredentialProcess:    section["credential_process"],
+		RoleSessionName:      section["role_session_name"],
+		WebIdentityTokenFile: section["web_identity_token_file"],
+		SSOSession:           section["sso_session"],
+		SSOStartURL:          section["sso_start_url"],
+		SSORegion:            section["sso_region"],
+		SSOAccountID:         section["sso_account_id"],
+		SSORoleName:          section["sso_role_name"],
+	}
+
+	if v, ok := section["external_id"]; ok && v != "" {
+		p.ExternalID = &v
+	}
+
+	if v, ok := section["mfa_serial"]; ok && v != "" {
+		p.MFASerial = &v
+	}
+
+	if v, ok := section["duration_seconds"]; ok && v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil {
+			err = fmt.Errorf("failed to parse duration_seconds of profile %s, %w", name, err)
+			return nil, err
+		}
+
+		duration := time.Duration(seconds) * time.Second
+		p.DurationSeconds = &duration
+	}
+
+	if p.SSOSession != "" {
+		ssoSession, ok := config["sso-session "+p.SSOSession]
+		if !ok {
+			err := fmt.Errorf("%w, sso-session %s", ErrProfileNotFound, p.SSOSession)
+			return nil, err
+		}
+
+		p.SSOStartURL = ssoSession["sso_start_url"]
+		p.SSORegion = ssoSession["sso_region"]
+	}
+
+	if p.RoleARN != "" && p.SourceProfileName != "" {
+		if p.SourceProfileName == name {
+			if !p.HasStaticCredentials {
+				err := fmt.Errorf("%w, %s", ErrSourceProfileCycle, name)
+				return nil, err
+			}
+		} else {
+			source, err := loadSharedProfile(p.SourceProfileName, config, credentials, visited)
+			if err != nil {
+				return nil, err
+			}
+
+			p.SourceProfile = source
+		}
+	}
+
+	return p, nil
+}
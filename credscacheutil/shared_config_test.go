@@ -0,0 +1,420 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSharedConfig = `
+[default]
+region = us-east-1
+
+[profile role]
+source_profile = default
+role_arn = arn:aws:iam::123456789012:role/role
+role_session_name = role_session_name
+external_id = external_id
+mfa_serial = arn:aws:iam::123456789012:mfa/user
+duration_seconds = 3600
+
+[profile chained]
+source_profile = role
+role_arn = arn:aws:iam::123456789012:role/chained
+
+[profile self]
+source_profile = self
+role_arn = arn:aws:iam::123456789012:role/self
+
+[profile cycle-a]
+source_profile = cycle-b
+role_arn = arn:aws:iam::123456789012:role/cycle-a
+
+[profile cycle-b]
+source_profile = cycle-a
+role_arn = arn:aws:iam::123456789012:role/cycle-b
+
+[profile short-duration]
+credential_source = Environment
+role_arn = arn:aws:iam::123456789012:role/short-duration
+duration_seconds = 900
+
+[profile process]
+credential_process = /usr/local/bin/credential-process
+
+[profile web-identity]
+role_arn = arn:aws:iam::123456789012:role/web-identity
+web_identity_token_file = /var/run/secrets/token
+
+[profile sso]
+sso_session = sso
+sso_account_id = 123456789012
+sso_role_name = role_name
+
+[profile legacy-sso]
+sso_start_url = https://d-123456789a.awsapps.com/start
+sso_region = us-east-1
+sso_account_id = 123456789012
+sso_role_name = role_name
+
+[profile invalid-duration]
+source_profile = default
+role_arn = arn:aws:iam::123456789012:role/role
+duration_seconds = one hour
+
+[sso-session sso]
+sso_start_url = https://d-123456789a.awsapps.com/start
+sso_region = us-east-1
+`
+
+const testSharedCredentials = `
+[default]
+aws_access_key_id = AccessKeyID
+aws_secret_access_key = SecretAccessKey
+
+[self]
+aws_access_key_id = AccessKeyID
+aws_secret_access_key = SecretAccessKey
+`
+
+func writeTestSharedConfig(t *testing.T) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config")
+	credentialsFile := filepath.Join(dir, "credentials")
+	os.WriteFile(configFile, []byte(testSharedConfig), 0600)
+	os.WriteFile(credentialsFile, []byte(testSharedCredentials), 0600)
+
+	return configFile, credentialsFile
+}
+
+func TestResolveProfileName(t *testing.T) {
+	type env struct {
+		profile        string
+		defaultProfile string
+	}
+
+	type args struct {
+		name string
+	}
+
+	type expected struct {
+		res string
+	}
+
+	tests := []struct {
+		name     string
+		env      env
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: specified",
+			env: env{
+				profile:        "env",
+				defaultProfile: "default_env",
+			},
+			args: args{
+				name: "specified",
+			},
+			expected: expected{
+				res: "specified",
+			},
+		},
+		{
+			name: "positive case: AWS_PROFILE",
+			env: env{
+				profile:        "env",
+				defaultProfile: "default_env",
+			},
+			args: args{
+				name: "",
+			},
+			expected: expected{
+				res: "env",
+			},
+		},
+		{
+			name: "positive case: AWS_DEFAULT_PROFILE",
+			env: env{
+				profile:        "",
+				defaultProfile: "default_env",
+			},
+			args: args{
+				name: "",
+			},
+			expected: expected{
+				res: "default_env",
+			},
+		},
+		{
+			name: "positive case: default",
+			env: env{
+				profile:        "",
+				defaultProfile: "",
+			},
+			args: args{
+				name: "",
+			},
+			expected: expected{
+				res: "default",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv(ProfileEnvVar, tt.env.profile)
+			t.Setenv(DefaultProfileEnvVar, tt.env.defaultProfile)
+
+			// Act
+			actual := ResolveProfileName(tt.args.name)
+
+			// Assert
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
+
+func TestLoadSharedProfile(t *testing.T) {
+	configFile, credentialsFile := writeTestSharedConfig(t)
+	duration := time.Duration(3600) * time.Second
+
+	defaultProfile := &SharedProfile{
+		Name:                 "default",
+		Region:               "us-east-1",
+		HasStaticCredentials: true,
+	}
+	roleProfile := &SharedProfile{
+		Name:              "role",
+		RoleARN:           "arn:aws:iam::123456789012:role/role",
+		SourceProfileName: "default",
+		SourceProfile:     defaultProfile,
+		ExternalID:        aws.String("external_id"),
+		MFASerial:         aws.String("arn:aws:iam::123456789012:mfa/user"),
+		DurationSeconds:   &duration,
+		RoleSessionName:   "role_session_name",
+	}
+
+	type args struct {
+		name string
+	}
+
+	type expected struct {
+		res *SharedProfile
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: default",
+			args: args{
+				name: "default",
+			},
+			expected: expected{
+				res: defaultProfile,
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: role",
+			args: args{
+				name: "role",
+			},
+			expected: expected{
+				res: roleProfile,
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: chained role",
+			args: args{
+				name: "chained",
+			},
+			expected: expected{
+				res: &SharedProfile{
+					Name:              "chained",
+					RoleARN:           "arn:aws:iam::123456789012:role/chained",
+					SourceProfileName: "role",
+					SourceProfile:     roleProfile,
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: self-referencing source_profile",
+			args: args{
+				name: "self",
+			},
+			expected: expected{
+				res: &SharedProfile{
+					Name:                 "self",
+					HasStaticCredentials: true,
+					RoleARN:              "arn:aws:iam::123456789012:role/self",
+					SourceProfileName:    "self",
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: sso-session",
+			args: args{
+				name: "sso",
+			},
+			expected: expected{
+				res: &SharedProfile{
+					Name:         "sso",
+					SSOSession:   "sso",
+					SSOStartURL:  "https://d-123456789a.awsapps.com/start",
+					SSORegion:    "us-east-1",
+					SSOAccountID: "123456789012",
+					SSORoleName:  "role_name",
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: profile not found",
+			args: args{
+				name: "non-existing",
+			},
+			expected: expected{
+				res: nil,
+				err: ErrProfileNotFound,
+			},
+		},
+		{
+			name: "negative case: source profile cycle",
+			args: args{
+				name: "cycle-a",
+			},
+			expected: expected{
+				res: nil,
+				err: ErrSourceProfileCycle,
+			},
+		},
+		{
+			name: "negative case: invalid duration_seconds",
+			args: args{
+				name: "invalid-duration",
+			},
+			expected: expected{
+				res: nil,
+				err: strconv.ErrSyntax,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := LoadSharedProfile(tt.args.name, func(o *SharedConfigOptions) {
+				o.ConfigFile = configFile
+				o.CredentialsFile = credentialsFile
+			})
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
+
+func TestLoadSharedProfiles(t *testing.T) {
+	configFile, credentialsFile := writeTestSharedConfig(t)
+
+	type args struct {
+		configFile      string
+		credentialsFile string
+	}
+
+	type expected struct {
+		names []string
+		err   error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: skip profiles that fail to load",
+			args: args{
+				configFile:      configFile,
+				credentialsFile: credentialsFile,
+			},
+			expected: expected{
+				names: []string{"chained", "default", "legacy-sso", "process", "role", "self", "short-duration", "sso", "web-identity"},
+				err:   nil,
+			},
+		},
+		{
+			name: "positive case: no shared config files",
+			args: args{
+				configFile:      filepath.Join(t.TempDir(), "config"),
+				credentialsFile: filepath.Join(t.TempDir(), "credentials"),
+			},
+			expected: expected{
+				names: []string{},
+				err:   nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := LoadSharedProfiles(func(o *SharedConfigOptions) {
+				o.ConfigFile = tt.args.configFile
+				o.CredentialsFile = tt.args.credentialsFile
+			})
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+
+				names := []string{}
+				for _, p := range actual {
+					names = append(names, p.Name)
+				}
+				assert.Equal(t, tt.expected.names, names)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
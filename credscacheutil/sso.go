@@ -0,0 +1,61 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type SSOCredentialsCacheKeyGenerator struct {
+	StartURL    string
+	RoleName    string
+	AccountID   string
+	SessionName string
+}
+
+var _ interface {
+	fmt.Stringer
+	CacheKeyer
+} = &SSOCredentialsCacheKeyGenerator{}
+
+func (g SSOCredentialsCacheKeyGenerator) String() string {
+	o := []string{}
+
+	o = append(o, fmt.Sprintf(`"accountId":"%s"`, g.AccountID))
+	o = append(o, fmt.Sprintf(`"roleName":"%s"`, g.RoleName))
+
+	// the AWS CLI keys sso-session profiles on the session name instead of the start URL
+	if g.SessionName != "" {
+		o = append(o, fmt.Sprintf(`"sessionName":"%s"`, g.SessionName))
+	} else {
+		o = append(o, fmt.Sprintf(`"startUrl":"%s"`, g.StartURL))
+	}
+
+	sort.Slice(o, func(i, j int) bool { return o[i] < o[j] })
+
+	return fmt.Sprintf("{%s}", strings.Join(o, ","))
+}
+
+func (g *SSOCredentialsCacheKeyGenerator) CacheKey() (string, error) {
+	return sha1Hex(g.String())
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSOCredentialsCacheKeyGenerator_String(t *testing.T) {
+	type expected struct {
+		res string
+	}
+
+	tests := []struct {
+		name      string
+		generator SSOCredentialsCacheKeyGenerator
+		expected  expected
+	}{
+		{
+			name: "positive case: with StartURL, RoleName, AccountID",
+			generator: SSOCredentialsCacheKeyGenerator{
+				StartURL:  "https://d-123456789a.awsapps.com/start",
+				RoleName:  "role_name",
+				AccountID: "123456789012",
+			},
+			expected: expected{
+				res: `{"accountId":"123456789012","roleName":"role_name","startUrl":"https://d-123456789a.awsapps.com/start"}`,
+			},
+		},
+		{
+			name: "positive case: with StartURL, RoleName, AccountID, SessionName",
+			generator: SSOCredentialsCacheKeyGenerator{
+				StartURL:    "https://d-123456789a.awsapps.com/start",
+				RoleName:    "role_name",
+				AccountID:   "123456789012",
+				SessionName: "session_name",
+			},
+			expected: expected{
+				res: `{"accountId":"123456789012","roleName":"role_name","sessionName":"session_name"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.generator.String()
+
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
+
+func TestSSOCredentialsCacheKeyGenerator_CacheKey(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name      string
+		generator SSOCredentialsCacheKeyGenerator
+		expected  expected
+	}{
+		{
+			name: "positive case: with StartURL, RoleName, AccountID",
+			generator: SSOCredentialsCacheKeyGenerator{
+				StartURL:  "https://d-123456789a.awsapps.com/start",
+				RoleName:  "role_name",
+				AccountID: "123456789012",
+			},
+			expected: expected{
+				res: "dd6d574879f4e925fe793f9c637e83402905e93e",
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: with StartURL, RoleName, AccountID, SessionName",
+			generator: SSOCredentialsCacheKeyGenerator{
+				StartURL:    "https://d-123456789a.awsapps.com/start",
+				RoleName:    "role_name",
+				AccountID:   "123456789012",
+				SessionName: "session_name",
+			},
+			expected: expected{
+				res: "ade5dd1dd1ad1e55ac4da94f0f1af7bb26a8648b",
+				err: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.generator.CacheKey()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	defaultSSOTokenCacheDir = "sso/cache"
+)
+
+type SSOToken struct {
+	AccessToken           string     `json:"accessToken"`
+	ExpiresAt             time.Time  `json:"expiresAt"`
+	RefreshToken          string     `json:"refreshToken,omitempty"`
+	ClientID              string     `json:"clientId,omitempty"`
+	ClientSecret          string     `json:"clientSecret,omitempty"`
+	RegistrationExpiresAt *time.Time `json:"registrationExpiresAt,omitempty"`
+	Region                string     `json:"region,omitempty"`
+	StartURL              string     `json:"startUrl,omitempty"`
+}
+
+var _ interface {
+	Loader
+	Storer
+} = &SSOToken{}
+
+func (t *SSOToken) Load(path string, optFns ...func(o *FileOptions)) error {
+	data, err := readFile(path, optFns...)
+	if err != nil {
+		return err
+	}
+
+	token := new(SSOToken)
+	if err := json.Unmarshal(data, token); err != nil {
+		err = fmt.Errorf("failed to decode sso token json, %w", err)
+		return err
+	}
+
+	*t = *token
+
+	return nil
+}
+
+func (t *SSOToken) Store(path string, optFns ...func(o *FileOptions)) error {
+	token := *t
+	token.ExpiresAt = token.ExpiresAt.UTC().Truncate(time.Second)
+	if token.RegistrationExpiresAt != nil {
+		registrationExpiresAt := token.RegistrationExpiresAt.UTC().Truncate(time.Second)
+		token.RegistrationExpiresAt = &registrationExpiresAt
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		err = fmt.Errorf("failed to encode sso token json, %w", err)
+		return err
+	}
+
+	return writeFile(path, data, optFns...)
+}
+
+type SSOTokenCacheKeyGenerator struct {
+	SessionName string
+	StartURL    string
+}
+
+var _ interface {
+	fmt.Stringer
+	CacheKeyer
+} = &SSOTokenCacheKeyGenerator{}
+
+func (g SSOTokenCacheKeyGenerator) String() string {
+	if g.SessionName != "" {
+		return g.SessionName
+	}
+
+	return g.StartURL
+}
+
+func (g *SSOTokenCacheKeyGenerator) CacheKey() (string, error) {
+	return sha1Hex(g.String())
+}
+
+func DefaultSSOTokenCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		err = fmt.Errorf("failed to resolve home directory, %w", err)
+		return "", err
+	}
+
+	return filepath.Join(home, defaultConfigDir, filepath.FromSlash(defaultSSOTokenCacheDir)), nil
+}
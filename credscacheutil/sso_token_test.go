@@ -0,0 +1,268 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscacheutil
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSOToken_Load(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, "session.json"), []byte(`{
+  "startUrl": "https://d-123456789a.awsapps.com/start",
+  "region": "us-east-1",
+  "accessToken": "AccessToken",
+  "expiresAt": "2006-01-02T15:04:05Z",
+  "clientId": "ClientID",
+  "clientSecret": "ClientSecret",
+  "registrationExpiresAt": "2006-04-02T15:04:05Z",
+  "refreshToken": "RefreshToken"
+}`), 0600)
+
+	registrationExpiresAt := time.Date(2006, 4, 2, 15, 4, 5, 0, time.UTC)
+
+	type args struct {
+		path string
+	}
+
+	type expected struct {
+		token *SSOToken
+		err   error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: sso-session token",
+			args: args{
+				path: filepath.Join(tempDir, "session.json"),
+			},
+			expected: expected{
+				token: &SSOToken{
+					AccessToken:           "AccessToken",
+					ExpiresAt:             time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+					RefreshToken:          "RefreshToken",
+					ClientID:              "ClientID",
+					ClientSecret:          "ClientSecret",
+					RegistrationExpiresAt: &registrationExpiresAt,
+					Region:                "us-east-1",
+					StartURL:              "https://d-123456789a.awsapps.com/start",
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: no such file",
+			args: args{
+				path: filepath.Join(tempDir, "non-existing.json"),
+			},
+			expected: expected{
+				token: nil,
+				err:   syscall.Errno(2),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := new(SSOToken)
+			err := actual.Load(tt.args.path)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.token, actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
+
+func TestSSOToken_Store(t *testing.T) {
+	registrationExpiresAt := time.Date(2006, 4, 2, 15, 4, 5, 0, time.UTC)
+
+	type args struct {
+		path string
+	}
+
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		token    *SSOToken
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: sso-session token",
+			token: &SSOToken{
+				AccessToken:           "AccessToken",
+				ExpiresAt:             time.Date(2006, 1, 2, 15, 4, 5, 999, time.UTC),
+				RefreshToken:          "RefreshToken",
+				ClientID:              "ClientID",
+				ClientSecret:          "ClientSecret",
+				RegistrationExpiresAt: &registrationExpiresAt,
+			},
+			args: args{
+				path: filepath.Join(t.TempDir(), "non-existing/session.json"),
+			},
+			expected: expected{
+				res: `{"accessToken":"AccessToken","expiresAt":"2006-01-02T15:04:05Z","refreshToken":"RefreshToken","clientId":"ClientID","clientSecret":"ClientSecret","registrationExpiresAt":"2006-04-02T15:04:05Z"}`,
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: legacy token",
+			token: &SSOToken{
+				AccessToken: "AccessToken",
+				ExpiresAt:   time.Date(2006, 1, 3, 0, 4, 5, 0, time.FixedZone("Asia/Tokyo", 9*60*60)),
+				Region:      "us-east-1",
+				StartURL:    "https://d-123456789a.awsapps.com/start",
+			},
+			args: args{
+				path: filepath.Join(t.TempDir(), "legacy.json"),
+			},
+			expected: expected{
+				res: `{"accessToken":"AccessToken","expiresAt":"2006-01-02T15:04:05Z","region":"us-east-1","startUrl":"https://d-123456789a.awsapps.com/start"}`,
+				err: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.token.Store(tt.args.path)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				actual, _ := os.ReadFile(tt.args.path)
+				assert.Equal(t, tt.expected.res, string(actual))
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
+
+func TestSSOTokenCacheKeyGenerator_CacheKey(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name      string
+		generator SSOTokenCacheKeyGenerator
+		expected  expected
+	}{
+		{
+			name: "positive case: with SessionName",
+			generator: SSOTokenCacheKeyGenerator{
+				SessionName: "my-sso",
+				StartURL:    "https://d-123456789a.awsapps.com/start",
+			},
+			expected: expected{
+				res: "0ad374308c5a4e22f723adf10145eafad7c4031c",
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: with StartURL",
+			generator: SSOTokenCacheKeyGenerator{
+				StartURL: "https://d-123456789a.awsapps.com/start",
+			},
+			expected: expected{
+				res: "2522b24e4bb6b800675965656425e3d136c896ad",
+				err: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.generator.CacheKey()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
+
+func TestDefaultSSOTokenCacheDir(t *testing.T) {
+	home := t.TempDir()
+
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		expected expected
+	}{
+		{
+			name: "positive case: home directory",
+			expected: expected{
+				res: filepath.Join(home, ".aws", "sso", "cache"),
+				err: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("HOME", home)
+			t.Setenv("USERPROFILE", home)
+
+			// Act
+			actual, err := DefaultSSOTokenCacheDir()
+
+			// Assert
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
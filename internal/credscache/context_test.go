@@ -0,0 +1,98 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinLifetime(t *testing.T) {
+	type args struct {
+		ctx context.Context
+	}
+
+	type expected struct {
+		res time.Duration
+		ok  bool
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: min lifetime",
+			args: args{
+				ctx: WithMinLifetime(context.Background(), time.Duration(45)*time.Minute),
+			},
+			expected: expected{
+				res: time.Duration(45) * time.Minute,
+				ok:  true,
+			},
+		},
+		{
+			name: "positive case: overridden min lifetime",
+			args: args{
+				ctx: WithMinLifetime(WithMinLifetime(context.Background(), time.Duration(45)*time.Minute), time.Duration(1)*time.Minute),
+			},
+			expected: expected{
+				res: time.Duration(1) * time.Minute,
+				ok:  true,
+			},
+		},
+		{
+			name: "positive case: no min lifetime",
+			args: args{
+				ctx: context.Background(),
+			},
+			expected: expected{
+				res: 0,
+				ok:  false,
+			},
+		},
+		{
+			name: "positive case: nil context",
+			args: args{
+				ctx: nil,
+			},
+			expected: expected{
+				res: 0,
+				ok:  false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, ok := MinLifetime(tt.args.ctx)
+
+			// Assert
+			assert.Equal(t, tt.expected.res, actual)
+			assert.Equal(t, tt.expected.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDurationValidationError(t *testing.T) {
+	type args struct {
+		code    string
+		message string
+	}
+
+	type expected struct {
+		res bool
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: DurationSeconds exceeds MaxSessionDuration",
+			args: args{
+				code:    "ValidationError",
+				message: "The requested DurationSeconds exceeds the MaxSessionDuration set for this role.",
+			},
+			expected: expected{
+				res: true,
+			},
+		},
+		{
+			name: "positive case: other validation error",
+			args: args{
+				code:    "ValidationError",
+				message: "1 validation error detected: Value at 'roleArn' failed to satisfy constraint",
+			},
+			expected: expected{
+				res: false,
+			},
+		},
+		{
+			name: "positive case: AccessDenied",
+			args: args{
+				code:    "AccessDenied",
+				message: "User is not authorized to perform: sts:AssumeRole",
+			},
+			expected: expected{
+				res: false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := IsDurationValidationError(tt.args.code, tt.args.message)
+
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
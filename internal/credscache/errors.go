@@ -26,7 +26,12 @@ import (
 )
 
 var (
-	ErrNilPointer = errors.New("nil pointer")
+	ErrNilPointer         = errors.New("nil pointer")
+	ErrUnsupportedCommand = errors.New("unsupported command")
+	ErrSSOConfigNotFound  = errors.New("sso config not found")
+	ErrTokenProviderUnset = errors.New("token provider unset")
+	ErrUnsupportedClient  = errors.New("unsupported client")
+	ErrAmbiguousProfile   = errors.New("ambiguous profile")
 )
 
 type FileCacheProviderError struct {
@@ -52,3 +57,24 @@ func (e *InjectionError) Error() string {
 func (e *InjectionError) Unwrap() error {
 	return e.Err
 }
+
+type InsecureCacheError struct {
+	Path   string
+	Reason string
+}
+
+func (e *InsecureCacheError) Error() string {
+	return fmt.Sprintf("insecure cache %s, %s", e.Path, e.Reason)
+}
+
+type SSOLoginError struct {
+	Err error
+}
+
+func (e *SSOLoginError) Error() string {
+	return fmt.Sprintf("sso login error: %v", e.Err)
+}
+
+func (e *SSOLoginError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SkipReason int
+
+const (
+	SkipReasonNone SkipReason = iota
+	SkipReasonNoCredentials
+	SkipReasonUnsupportedProvider
+	SkipReasonUnsupportedCommand
+	SkipReasonAlreadyInjected
+	SkipReasonAmbiguousProfile
+)
+
+func (r SkipReason) String() string {
+	switch r {
+	case SkipReasonNone:
+		return "none"
+	case SkipReasonNoCredentials:
+		return "no credentials provider"
+	case SkipReasonUnsupportedProvider:
+		return "unsupported credentials provider"
+	case SkipReasonUnsupportedCommand:
+		return "unsupported credential process command"
+	case SkipReasonAlreadyInjected:
+		return "file cache provider already injected"
+	case SkipReasonAmbiguousProfile:
+		return "ambiguous shared config profile"
+	default:
+		return "unknown"
+	}
+}
+
+type InjectionResult struct {
+	Injected      bool
+	ProviderChain []string
+	Wrapped       string
+	CacheKey      string
+	Path          string
+	Reason        SkipReason
+}
+
+func (r *InjectionResult) String() string {
+	chain := strings.Join(r.ProviderChain, " > ")
+
+	if !r.Injected {
+		return fmt.Sprintf("file cache provider not injected, %s, provider chain [%s]", r.Reason, chain)
+	}
+
+	return fmt.Sprintf("file cache provider injected, wrapped %s, cache key %s, path %s, provider chain [%s]", r.Wrapped, r.CacheKey, r.Path, chain)
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipReason_String(t *testing.T) {
+	type expected struct {
+		res string
+	}
+
+	tests := []struct {
+		name     string
+		reason   SkipReason
+		expected expected
+	}{
+		{
+			name:   "positive case: None",
+			reason: SkipReasonNone,
+			expected: expected{
+				res: "none",
+			},
+		},
+		{
+			name:   "positive case: NoCredentials",
+			reason: SkipReasonNoCredentials,
+			expected: expected{
+				res: "no credentials provider",
+			},
+		},
+		{
+			name:   "positive case: UnsupportedProvider",
+			reason: SkipReasonUnsupportedProvider,
+			expected: expected{
+				res: "unsupported credentials provider",
+			},
+		},
+		{
+			name:   "positive case: UnsupportedCommand",
+			reason: SkipReasonUnsupportedCommand,
+			expected: expected{
+				res: "unsupported credential process command",
+			},
+		},
+		{
+			name:   "positive case: AlreadyInjected",
+			reason: SkipReasonAlreadyInjected,
+			expected: expected{
+				res: "file cache provider already injected",
+			},
+		},
+		{
+			name:   "positive case: AmbiguousProfile",
+			reason: SkipReasonAmbiguousProfile,
+			expected: expected{
+				res: "ambiguous shared config profile",
+			},
+		},
+		{
+			name:   "positive case: unknown",
+			reason: SkipReason(-1),
+			expected: expected{
+				res: "unknown",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.reason.String()
+
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
+
+func TestInjectionResult_String(t *testing.T) {
+	type expected struct {
+		res string
+	}
+
+	tests := []struct {
+		name     string
+		result   *InjectionResult
+		expected expected
+	}{
+		{
+			name: "positive case: injected",
+			result: &InjectionResult{
+				Injected:      true,
+				ProviderChain: []string{"*aws.CredentialsCache", "*stscreds.AssumeRoleProvider"},
+				Wrapped:       "*stscreds.AssumeRoleProvider",
+				CacheKey:      "key",
+				Path:          "/home/user/.aws/cli/cache/key.json",
+				Reason:        SkipReasonNone,
+			},
+			expected: expected{
+				res: "file cache provider injected, wrapped *stscreds.AssumeRoleProvider, cache key key, path /home/user/.aws/cli/cache/key.json, provider chain [*aws.CredentialsCache > *stscreds.AssumeRoleProvider]",
+			},
+		},
+		{
+			name: "positive case: not injected",
+			result: &InjectionResult{
+				Injected:      false,
+				ProviderChain: []string{"*aws.CredentialsCache", "*credentials.StaticCredentialsProvider"},
+				Reason:        SkipReasonUnsupportedProvider,
+			},
+			expected: expected{
+				res: "file cache provider not injected, unsupported credentials provider, provider chain [*aws.CredentialsCache > *credentials.StaticCredentialsProvider]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.result.String()
+
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
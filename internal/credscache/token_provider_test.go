@@ -0,0 +1,98 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoizeTokenProvider(t *testing.T) {
+	errTokenFailure := errors.New("failed to read token")
+
+	type args struct {
+		token string
+		err   error
+		calls int
+	}
+
+	type expected struct {
+		res   string
+		calls int
+		err   error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: token",
+			args: args{
+				token: "123456",
+				calls: 3,
+			},
+			expected: expected{
+				res:   "123456",
+				calls: 1,
+			},
+		},
+		{
+			name: "negative case: token failure",
+			args: args{
+				err:   errTokenFailure,
+				calls: 3,
+			},
+			expected: expected{
+				calls: 1,
+				err:   errTokenFailure,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			calls := 0
+			tokenProvider := MemoizeTokenProvider(func() (string, error) {
+				calls++
+				return tt.args.token, tt.args.err
+			})
+
+			for i := 0; i < tt.args.calls; i++ {
+				// Act
+				actual, err := tokenProvider()
+
+				// Assert
+				if tt.expected.err == nil {
+					assert.NoError(t, err)
+					assert.Equal(t, tt.expected.res, actual)
+				} else {
+					assert.ErrorIs(t, err, tt.expected.err)
+				}
+			}
+			assert.Equal(t, tt.expected.calls, calls)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ini
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type Sections map[string]Section
+
+type Section map[string]string
+
+func Parse(r io.Reader) (Sections, error) {
+	sections := Sections{}
+
+	var section Section
+	nested := false
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+
+		if isComment(line) {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			// the SDK parsers ignore whatever follows the header, e.g. a comment
+			end := strings.Index(line, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid section header at line %d", n)
+			}
+
+			name := strings.Join(strings.Fields(line[1:end]), " ")
+			if _, ok := sections[name]; !ok {
+				sections[name] = Section{}
+			}
+			section = sections[name]
+			nested = false
+			continue
+		}
+
+		if nested && raw != line && (raw[0] == ' ' || raw[0] == '\t') {
+			continue
+		}
+
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 {
+			return nil, fmt.Errorf("invalid property at line %d", n)
+		}
+		key, value := line[:sep], line[sep+1:]
+
+		if section == nil {
+			return nil, fmt.Errorf("property outside of section at line %d", n)
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = unquote(trimInlineComment(strings.TrimSpace(value)))
+		nested = value == ""
+		section[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return sections, nil
+}
+
+func isComment(line string) bool {
+	return line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";")
+}
+
+// trimInlineComment removes a comment that follows a value after whitespace.
+// A "#" or ";" within the value, e.g. in a URL fragment, is kept.
+func trimInlineComment(value string) string {
+	for i := 1; i < len(value); i++ {
+		if (value[i] == '#' || value[i] == ';') && (value[i-1] == ' ' || value[i-1] == '\t') {
+			return strings.TrimSpace(value[:i])
+		}
+	}
+
+	return value
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
+
+func ParseFile(path string) (Sections, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Sections{}, nil
+		}
+
+		err = fmt.Errorf("failed to open ini file, %w", err)
+		return nil, err
+	}
+	defer f.Close()
+
+	sections, err := Parse(f)
+	if err != nil {
+		err = fmt.Errorf("failed to parse ini file %s, %w", path, err)
+		return nil, err
+	}
+
+	return sections, nil
+}
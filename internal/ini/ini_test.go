@@ -0,0 +1,302 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ini
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	type args struct {
+		data string
+	}
+
+	type expected struct {
+		res Sections
+		err bool
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: sections",
+			args: args{
+				data: `
+# comment
+[default]
+region = us-east-1
+
+; comment
+[profile  myprofile]
+Role_ARN=arn:aws:iam::123456789012:role/myrole
+source_profile = default
+`,
+			},
+			expected: expected{
+				res: Sections{
+					"default": Section{
+						"region": "us-east-1",
+					},
+					"profile myprofile": Section{
+						"role_arn":       "arn:aws:iam::123456789012:role/myrole",
+						"source_profile": "default",
+					},
+				},
+				err: false,
+			},
+		},
+		{
+			name: "positive case: nested section",
+			args: args{
+				data: `
+[default]
+s3 =
+  max_concurrent_requests = 20
+  addressing_style = path
+region = us-east-1
+`,
+			},
+			expected: expected{
+				res: Sections{
+					"default": Section{
+						"s3":     "",
+						"region": "us-east-1",
+					},
+				},
+				err: false,
+			},
+		},
+		{
+			name: "positive case: duplicated section",
+			args: args{
+				data: `
+[default]
+region = us-east-1
+[default]
+output = json
+`,
+			},
+			expected: expected{
+				res: Sections{
+					"default": Section{
+						"region": "us-east-1",
+						"output": "json",
+					},
+				},
+				err: false,
+			},
+		},
+		{
+			name: "positive case: text after section headers",
+			args: args{
+				data: `
+[default] # comment
+region = us-east-1
+[profile a] ; comment
+region = us-west-2
+[profile b]#comment
+region = eu-west-1
+[profile c] region = ignored
+region = ap-northeast-1
+`,
+			},
+			expected: expected{
+				res: Sections{
+					"default": Section{
+						"region": "us-east-1",
+					},
+					"profile a": Section{
+						"region": "us-west-2",
+					},
+					"profile b": Section{
+						"region": "eu-west-1",
+					},
+					"profile c": Section{
+						"region": "ap-northeast-1",
+					},
+				},
+				err: false,
+			},
+		},
+		{
+			name: "positive case: comments after values",
+			args: args{
+				data: `
+[default]
+region = us-east-1 # comment
+output = json ; comment
+sso_start_url = https://example.awsapps.com/start#/
+credential_process = process;arg
+`,
+			},
+			expected: expected{
+				res: Sections{
+					"default": Section{
+						"region":             "us-east-1",
+						"output":             "json",
+						"sso_start_url":      "https://example.awsapps.com/start#/",
+						"credential_process": "process;arg",
+					},
+				},
+				err: false,
+			},
+		},
+		{
+			name: "positive case: colon separators and quoted values",
+			args: args{
+				data: `
+[default]
+region: us-east-1
+role_arn = arn:aws:iam::123456789012:role/myrole
+output = "json"
+`,
+			},
+			expected: expected{
+				res: Sections{
+					"default": Section{
+						"region":   "us-east-1",
+						"role_arn": "arn:aws:iam::123456789012:role/myrole",
+						"output":   "json",
+					},
+				},
+				err: false,
+			},
+		},
+		{
+			name: "negative case: invalid section header",
+			args: args{
+				data: `
+[default
+region = us-east-1
+`,
+			},
+			expected: expected{
+				res: nil,
+				err: true,
+			},
+		},
+		{
+			name: "negative case: invalid property",
+			args: args{
+				data: `
+[default]
+region
+`,
+			},
+			expected: expected{
+				res: nil,
+				err: true,
+			},
+		},
+		{
+			name: "negative case: property outside of section",
+			args: args{
+				data: `
+region = us-east-1
+`,
+			},
+			expected: expected{
+				res: nil,
+				err: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Parse(strings.NewReader(tt.args.data))
+
+			if !tt.expected.err {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, "config"), []byte("[default]\nregion = us-east-1\n"), 0600)
+
+	type args struct {
+		path string
+	}
+
+	type expected struct {
+		res Sections
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: existing file",
+			args: args{
+				path: filepath.Join(tempDir, "config"),
+			},
+			expected: expected{
+				res: Sections{
+					"default": Section{
+						"region": "us-east-1",
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: non-existing file",
+			args: args{
+				path: filepath.Join(tempDir, "non-existing"),
+			},
+			expected: expected{
+				res: Sections{},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseFile(tt.args.path)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
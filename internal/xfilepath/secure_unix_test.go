@@ -0,0 +1,197 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build unix
+
+package xfilepath
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenFileNoFollow(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, "file"), []byte{}, 0600)
+	os.Symlink(filepath.Join(tempDir, "file"), filepath.Join(tempDir, "symlink"))
+
+	type args struct {
+		path string
+	}
+
+	type expected struct {
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: regular file",
+			args: args{
+				path: filepath.Join(tempDir, "file"),
+			},
+			expected: expected{
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: symbolic link",
+			args: args{
+				path: filepath.Join(tempDir, "symlink"),
+			},
+			expected: expected{
+				err: ErrSymlink,
+			},
+		},
+		{
+			name: "negative case: no such file",
+			args: args{
+				path: filepath.Join(tempDir, "non-existing"),
+			},
+			expected: expected{
+				err: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := OpenFileNoFollow(tt.args.path, os.O_RDONLY, 0)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				f.Close()
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
+
+func TestIsOwnedByCurrentUser(t *testing.T) {
+	type args struct {
+		path string
+	}
+
+	type expected struct {
+		res bool
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: owned",
+			args: args{
+				path: t.TempDir(),
+			},
+			expected: expected{
+				res: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fi, _ := os.Stat(tt.args.path)
+
+			actual := IsOwnedByCurrentUser(fi)
+
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
+
+func TestIsWritableByOthers(t *testing.T) {
+	type args struct {
+		perm os.FileMode
+	}
+
+	type expected struct {
+		res bool
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: 0700",
+			args: args{
+				perm: 0700,
+			},
+			expected: expected{
+				res: false,
+			},
+		},
+		{
+			name: "positive case: 0755",
+			args: args{
+				perm: 0755,
+			},
+			expected: expected{
+				res: false,
+			},
+		},
+		{
+			name: "positive case: 0775",
+			args: args{
+				perm: 0775,
+			},
+			expected: expected{
+				res: true,
+			},
+		},
+		{
+			name: "positive case: 0757",
+			args: args{
+				perm: 0757,
+			},
+			expected: expected{
+				res: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			dir := t.TempDir()
+			os.Chmod(dir, tt.args.perm)
+			fi, _ := os.Stat(dir)
+
+			// Act
+			actual := IsWritableByOthers(fi)
+
+			// Assert
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
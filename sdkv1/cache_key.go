@@ -21,16 +21,82 @@
 package credscache
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/credentials/processcreds"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/service/sts"
 )
 
-func AssumeRoleCacheKey(provider *stscreds.AssumeRoleProvider) (string, error) {
-	duration := provider.Duration
-	if duration <= stscreds.DefaultDuration {
-		duration = time.Duration(0)
+type AssumeRoleCacheKeyOptions struct {
+	SharedProfile   *credscacheutil.SharedProfile
+	STSEndpoint     bool
+	SourceAccessKey bool
+}
+
+type SessionTokenCacheKeyOptions struct {
+	SourceAccessKey bool
+}
+
+func AssumeRoleCacheKey(provider *stscreds.AssumeRoleProvider, optFns ...func(o *AssumeRoleCacheKeyOptions)) (string, error) {
+	o := AssumeRoleCacheKeyOptions{}
+
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	if o.SourceAccessKey {
+		k, err := assumeRoleSourceAccessKeyCacheKey(provider, optFns...)
+		if err != nil {
+			return "", err
+		}
+
+		return k.resolve(context.Background())
+	}
+
+	g, err := assumeRoleCacheKeyGenerator(provider, o)
+	if err != nil {
+		return "", err
+	}
+
+	return g.CacheKey()
+}
+
+// assumeRoleSourceAccessKeyCacheKey defers the source access key hash of an
+// assume role cache key to the first retrieval.
+func assumeRoleSourceAccessKeyCacheKey(provider *stscreds.AssumeRoleProvider, optFns ...func(o *AssumeRoleCacheKeyOptions)) (*sourceAccessKeyCacheKey, error) {
+	o := AssumeRoleCacheKeyOptions{}
+
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	g, err := assumeRoleCacheKeyGenerator(provider, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSourceAccessKeyCacheKey(provider.Client, func(hash string) (string, error) {
+		g.SourceAccessKeyHash = hash
+		return g.CacheKey()
+	})
+}
+
+func assumeRoleCacheKeyGenerator(provider *stscreds.AssumeRoleProvider, o AssumeRoleCacheKeyOptions) (*credscacheutil.AssumeRoleCacheKeyGenerator, error) {
+	var duration time.Duration
+	if p := o.SharedProfile; p != nil && p.RoleARN == provider.RoleARN {
+		// the profile tells whether duration_seconds was set, which the provider
+		// loses once the session drops short durations or Retrieve sets a default
+		if p.DurationSeconds != nil {
+			duration = *p.DurationSeconds
+		}
+	} else if provider.Duration > stscreds.DefaultDuration {
+		duration = provider.Duration
 	}
 
 	g := &credscacheutil.AssumeRoleCacheKeyGenerator{
@@ -41,5 +107,102 @@ func AssumeRoleCacheKey(provider *stscreds.AssumeRoleProvider) (string, error) {
 		Duration:        duration,
 	}
 
+	if o.STSEndpoint {
+		info, err := stsClientInfo(provider.Client)
+		if err != nil {
+			return nil, err
+		}
+
+		g.Partition = info.PartitionID
+		g.STSEndpoint = info.Endpoint
+	}
+
+	return g, nil
+}
+
+func SessionTokenCacheKey(provider *SessionTokenProvider, profile string, optFns ...func(o *SessionTokenCacheKeyOptions)) (string, error) {
+	if provider == nil {
+		return "", ErrNilPointer
+	}
+
+	o := SessionTokenCacheKeyOptions{}
+
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	if o.SourceAccessKey {
+		k, err := sessionTokenSourceAccessKeyCacheKey(provider, profile)
+		if err != nil {
+			return "", err
+		}
+
+		return k.resolve(context.Background())
+	}
+
+	return sessionTokenCacheKeyGenerator(provider, profile).CacheKey()
+}
+
+// sessionTokenSourceAccessKeyCacheKey defers the source access key hash of a
+// session token cache key to the first retrieval.
+func sessionTokenSourceAccessKeyCacheKey(provider *SessionTokenProvider, profile string) (*sourceAccessKeyCacheKey, error) {
+	if provider == nil {
+		return nil, ErrNilPointer
+	}
+
+	g := sessionTokenCacheKeyGenerator(provider, profile)
+
+	return newSourceAccessKeyCacheKey(provider.Client, func(hash string) (string, error) {
+		g.SourceAccessKeyHash = hash
+		return g.CacheKey()
+	})
+}
+
+func sessionTokenCacheKeyGenerator(provider *SessionTokenProvider, profile string) *credscacheutil.SessionTokenCacheKeyGenerator {
+	return &credscacheutil.SessionTokenCacheKeyGenerator{
+		SerialNumber: provider.SerialNumber,
+		Duration:     provider.Duration,
+		Profile:      profile,
+	}
+}
+
+func stsClient(c interface{}) (*sts.STS, error) {
+	client, ok := c.(*sts.STS)
+	if !ok || client == nil || client.Client == nil {
+		err := fmt.Errorf("%w, sts client %T", ErrUnsupportedClient, c)
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// stsClientInfo returns the client info of an STS client, whose endpoint
+// reflects the region, the partition and sts_regional_endpoints.
+func stsClientInfo(c interface{}) (metadata.ClientInfo, error) {
+	client, err := stsClient(c)
+	if err != nil {
+		return metadata.ClientInfo{}, err
+	}
+
+	return client.ClientInfo, nil
+}
+
+func ProcessCacheKey(provider *processcreds.ProcessProvider, profile string) (string, error) {
+	accessor, err := NewProcessProviderUnsafeAccessor(provider)
+	if err != nil {
+		return "", err
+	}
+
+	args := accessor.Command()
+	if len(args) == 0 {
+		err := fmt.Errorf("%w, empty command", ErrUnsupportedCommand)
+		return "", err
+	}
+
+	g := &credscacheutil.ProcessCacheKeyGenerator{
+		Command: strings.Join(args, " "),
+		Profile: profile,
+	}
+
 	return g.CacheKey()
 }
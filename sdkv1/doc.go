@@ -23,8 +23,10 @@
 //
 // # Inject the file cache provider
 //
-// By default, the file cache provider outputs cache files to the current
-// directory.
+// By default, the file cache provider outputs cache files to
+// `$HOME/.aws/cli/cache` and shares cache with the AWS CLI (experimental
+// feature). The directory next to `AWS_CONFIG_FILE` is used if it is set, and
+// `AWS_CREDSCACHE_FILE_CACHE_DIR` overrides both.
 //
 //	sess, err := session.NewSessionWithOptions(session.Options{
 //		SharedConfigState:       session.SharedConfigEnable,
@@ -34,17 +36,19 @@
 //		log.Fatal(err)
 //	}
 //
-//	injected, err := credscache.InjectFileCacheProvider(sess.Config)
+//	result, err := credscache.InjectFileCacheProvider(sess.Config)
 //	if err != nil {
 //		log.Fatal(err)
 //	}
 //
-//	if !injected {
-//		log.Print("unable to inject file cache provider")
+//	if !result.Injected {
+//		log.Print(result)
 //	}
 //
-// You can share cache with the AWS CLI by specifying `$HOME/.aws/cli/cache`
-// (experimental feature).
+// The returned InjectionResult describes the provider chain, what was wrapped,
+// the cache key and file path, or the SkipReason when nothing is injected.
+//
+// You can also specify the cache directory.
 //
 //	sess, err := session.NewSessionWithOptions(session.Options{
 //		SharedConfigState:       session.SharedConfigEnable,
@@ -54,15 +58,265 @@
 //		log.Fatal(err)
 //	}
 //
-//	injected, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+//	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
 //		home, _ := os.UserHomeDir()
-//		o.FileCacheDir = filepath.Join(home, ".aws/cli/cache")
+//		o.FileCacheDir = filepath.Join(home, ".cache/aws/credscache")
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	if !result.Injected {
+//		log.Print(result)
+//	}
+//
+// # Create a session with the file cache provider
+//
+// NewSession creates a session and injects the file cache provider in one
+// step. Credentials overridden later by session.Copy or client configs are
+// injected right before their first request is signed.
+//
+//	sess, result, err := credscache.NewSession(session.Options{
+//		SharedConfigState:       session.SharedConfigEnable,
+//		AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	if !result.Injected {
+//		log.Print(result)
+//	}
+//
+// # Read through multiple cache directories
+//
+// Tiers replaces FileCacheDir with an ordered list of cache directories. The
+// freshest unexpired entry among the readable tiers is used, and refreshed
+// credentials are written to every writable tier. An empty Dir is the default
+// cache directory.
+//
+//	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+//		o.Tiers = []credscacheutil.CacheTier{
+//			{Dir: "/var/cache/aws/team", Policy: credscacheutil.CacheTierPolicyReadWrite},
+//			{Dir: "", Policy: credscacheutil.CacheTierPolicyReadOnly},
+//		}
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Collect expired cache files
+//
+// Set GC to remove cache files that expired more than GCGracePeriod ago after
+// each successful store. A marker file limits collection to once per
+// GCInterval across processes.
+//
+//	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+//		o.GC = true
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Require a minimum remaining lifetime
+//
+// WithMinLifetime overrides ExpiryWindow for a single call, so that cached
+// credentials which will not last long enough are refreshed. The in-memory
+// credentials.Credentials in front of the file cache provider answers first,
+// so expire it before a call that needs a longer lifetime.
+//
+//	sess.Config.Credentials.Expire()
+//
+//	creds, err := sess.Config.Credentials.GetWithContext(credscache.WithMinLifetime(context.Background(), 45*time.Minute))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// For API calls, InvalidateOnMinLifetimeHandler expires it only when the
+// credentials in memory expire within the minimum lifetime of the request
+// context.
+//
+//	sess.Handlers.Sign.PushFrontNamed(credscache.InvalidateOnMinLifetimeHandler)
+//
+//	if _, err := sts.New(sess).GetCallerIdentityWithContext(credscache.WithMinLifetime(context.Background(), 45*time.Minute), &sts.GetCallerIdentityInput{}); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Invalidate and refresh cached credentials
+//
+// LookupFileCacheProvider returns the injected file cache provider. Invalidate
+// removes its cache file from every tier and expires it, and Refresh fetches
+// and stores new credentials immediately. Expire the credentials.Credentials
+// as well so that the next call reaches the file cache provider.
+//
+//	if provider, ok := credscache.LookupFileCacheProvider(sess.Config); ok {
+//		if err := provider.Invalidate(context.Background()); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//
+//	sess.Config.Credentials.Expire()
+//
+// # Retry on revoked credentials
+//
+// Cached credentials can be revoked before they expire. The
+// InvalidateOnExpiredTokenHandler recognizes ExpiredToken, InvalidClientTokenId
+// and RequestExpired errors, invalidates the file cache provider, expires the
+// credentials and lets the SDK retry the request once with fresh credentials.
+// Install it on the session before creating clients.
+//
+//	if _, err := credscache.InjectFileCacheProvider(sess.Config); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	sess.Handlers.Retry.PushBackNamed(credscache.InvalidateOnExpiredTokenHandler)
+//
+// # Prompt for MFA codes
+//
+// The injectors wire a prompt into an assume role provider that has an MFA
+// serial number but no token provider. The prompt shows the role ARN, profile
+// and cache path, and only runs when the cache misses. The session requires an
+// AssumeRoleTokenProvider for profiles with mfa_serial, so pass
+// credscacheutil.DeferredMFATokenProvider to let the injector replace it.
+// MFAPrompter selects where the code is read from.
+//
+//	sess, result, err := credscache.NewSession(session.Options{
+//		SharedConfigState:       session.SharedConfigEnable,
+//		AssumeRoleTokenProvider: credscacheutil.DeferredMFATokenProvider,
+//	}, func(o *credscache.FileCacheOptions) {
+//		o.MFAPrompter = credscacheutil.TTYMFAPrompter
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Cache MFA sessions for IAM users
+//
+// The SDK ignores mfa_serial in a profile without role_arn.
+// SessionTokenProvider calls GetSessionToken with the MFA code, and the
+// injectors cache its credentials under a key derived from the profile, the
+// serial number and the duration. The cached session can be the source
+// credentials of later AssumeRole calls.
+//
+//	profile, err := credscacheutil.LoadSharedProfile("")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	sess.Config.Credentials = credentials.NewCredentials(credscache.NewSessionTokenProvider(sess, func(p *credscache.SessionTokenProvider) {
+//		p.SerialNumber = profile.MFASerial
+//	}))
+//
+//	if _, err := credscache.InjectFileCacheProvider(sess.Config); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Share an MFA session between roles
+//
+// With MFASession, the injectors obtain a GetSessionToken session for the
+// source profile of an assume role provider with an MFA serial number, cache
+// it, and assume the role with it. Every role with the same source profile
+// reuses the session, so a single MFA code lasts for MFASessionDuration (12
+// hours by default). The source profile must have IAM user credentials, and
+// the role cache key still includes the serial number for AWS CLI
+// compatibility.
+//
+//	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+//		o.MFASession = true
+//		o.MFASessionDuration = 8 * time.Hour
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Fall back to shorter role sessions
+//
+// AssumeRole fails when duration_seconds exceeds the MaxSessionDuration of the
+// role. With DurationFallback, the file cache provider retries with every
+// whole hour below the requested duration down to one hour. The credentials
+// are cached under the key of the requested duration, the cache entry records
+// EffectiveDurationSeconds, and later refreshes keep the accepted duration.
+// The MFA token provider is asked for a code only once per refresh.
+//
+//	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+//		o.DurationFallback = true
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Separate caches by STS endpoint
+//
+// The AWS CLI keys role sessions on the AssumeRole parameters alone, so roles
+// with the same ARN shape in different partitions share a cache file. With
+// STSEndpointCacheKey, the partition and the endpoint of the STS client are
+// mixed into the cache key, and the endpoint follows sts_regional_endpoints.
+// The key no longer matches the AWS CLI.
+//
+//	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+//		o.STSEndpointCacheKey = true
 //	})
 //	if err != nil {
 //		log.Fatal(err)
 //	}
 //
-//	if !injected {
-//		log.Print("unable to inject file cache provider")
+// # Scope caches to the caller
+//
+// Users assuming the same role with the same session name share a cache file
+// when they point at a common cache directory. With SourceAccessKeyCacheKey,
+// a hash of the access key ID of the source credentials is mixed into the
+// role and MFA session cache keys, so one user never loads the session of
+// another. The source credentials are retrieved on the first retrieval with
+// the caller's context and again on every refresh, so the key follows a
+// rotated source key. Until then, the injection result has no cache key.
+//
+//	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+//		o.SourceAccessKeyCacheKey = true
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Cache credential process output
+//
+// Credentials from `credential_process` are cached as well when the process
+// returns an `Expiration`. The cache key is derived from the command line and
+// the profile, so pass the profile given to the session.
+//
+//	sess, err := session.NewSessionWithOptions(session.Options{
+//		Profile:           "my-process-profile",
+//		SharedConfigState: session.SharedConfigEnable,
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+//		o.Profile = "my-process-profile"
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Observe the file cache provider
+//
+// An observer receives cache hits, misses, expirations, refreshes, store
+// failures, corrupt entries and insecure entries. A store failure is reported
+// to the observer and the retrieved credentials are still returned unless
+// StrictStore is set. An insecure entry is skipped and never written to, even
+// with StrictStore.
+// Ready-made observers are available for `log/slog` and expvar counters.
+//
+//	counters := credscacheutil.NewCounterObserver()
+//	counters.Publish("credscache")
+//
+//	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+//		o.Observer = credscacheutil.MultiObserver{
+//			credscacheutil.NewSlogObserver(slog.Default()),
+//			counters,
+//		}
+//	})
+//	if err != nil {
+//		log.Fatal(err)
 //	}
 package credscache
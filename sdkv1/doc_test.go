@@ -23,15 +23,18 @@ package credscache_test
 import (
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
 	credscache "github.com/Aton-Kish/aws-credscache-go/sdkv1"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
 )
 
 func ExampleAssumeRoleCacheKey() {
@@ -62,6 +65,26 @@ func ExampleAssumeRoleCacheKey_withRoleSessionNameAndMFASerial() {
 	// cda918cacd9e1d1c71d510d187e90c5817e04b97
 }
 
+func ExampleAssumeRoleCacheKey_withSTSEndpoint() {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region: aws.String("us-gov-west-1"),
+	}))
+
+	key, err := credscache.AssumeRoleCacheKey(&stscreds.AssumeRoleProvider{
+		Client:  sts.New(sess),
+		RoleARN: "role_arn",
+	}, func(o *credscache.AssumeRoleCacheKeyOptions) {
+		o.STSEndpoint = true
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(key)
+	// Output:
+	// 6ada2148aec1c7d3434f4169f56ac4b97d7861ee
+}
+
 func ExampleLoadCredentials() {
 	path := "/home/gopher/.aws/cli/cache/de1969e7a880d858c9bef3ba110acf78869d4527.json"
 	creds, expires, err := credscache.LoadCredentials(path)
@@ -103,13 +126,13 @@ func ExampleInjectFileCacheProvider() {
 		log.Fatal(err)
 	}
 
-	injected, err := credscache.InjectFileCacheProvider(sess.Config)
+	result, err := credscache.InjectFileCacheProvider(sess.Config)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if !injected {
-		log.Print("unable to inject file cache provider")
+	if !result.Injected {
+		log.Print(result)
 	}
 }
 
@@ -122,15 +145,167 @@ func ExampleInjectFileCacheProvider_specifiedFileCacheDir() {
 		log.Fatal(err)
 	}
 
-	injected, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
 		home, _ := os.UserHomeDir()
-		o.FileCacheDir = filepath.Join(home, ".aws/cli/cache")
+		o.FileCacheDir = filepath.Join(home, ".cache/aws/credscache")
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !result.Injected {
+		log.Print(result)
+	}
+}
+
+func ExampleInjectFileCacheProvider_withTiers() {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState:       session.SharedConfigEnable,
+		AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+		home, _ := os.UserHomeDir()
+		o.Tiers = []credscacheutil.CacheTier{
+			{Dir: filepath.Join(home, ".cache/aws/credscache"), Policy: credscacheutil.CacheTierPolicyReadWrite},
+			{Dir: "", Policy: credscacheutil.CacheTierPolicyReadOnly},
+		}
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !result.Injected {
+		log.Print(result)
+	}
+}
+
+func ExampleInjectFileCacheProvider_withMFAPrompter() {
+	sess := session.Must(session.NewSessionWithOptions(session.Options{
+		SharedConfigState:       session.SharedConfigEnable,
+		AssumeRoleTokenProvider: credscacheutil.DeferredMFATokenProvider,
+	}))
+
+	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+		o.MFAPrompter = credscacheutil.NewCommandMFAPrompter("sh", "-c", `zenity --entry --text "$AWS_CREDSCACHE_MFA_PROMPT"`)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !result.Injected {
+		log.Print(result)
+	}
+}
+
+func ExampleInjectFileCacheProvider_withMFASession() {
+	sess := session.Must(session.NewSessionWithOptions(session.Options{
+		SharedConfigState:       session.SharedConfigEnable,
+		AssumeRoleTokenProvider: credscacheutil.DeferredMFATokenProvider,
+	}))
+
+	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+		o.MFASession = true
+		o.MFASessionDuration = time.Duration(8) * time.Hour
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !result.Injected {
+		log.Print(result)
+	}
+}
+
+func ExampleNewSessionTokenProvider() {
+	sess := session.Must(session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	}))
+
+	profile, err := credscacheutil.LoadSharedProfile("")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sess.Config.Credentials = credentials.NewCredentials(credscache.NewSessionTokenProvider(sess, func(p *credscache.SessionTokenProvider) {
+		p.SerialNumber = profile.MFASerial
+		if profile.DurationSeconds != nil {
+			p.Duration = *profile.DurationSeconds
+		}
+	}))
+
+	if _, err := credscache.InjectFileCacheProvider(sess.Config); err != nil {
+		log.Fatal(err)
+	}
+
+	// the cached MFA session is the source credentials of the role
+	roleSess := sess.Copy(&aws.Config{
+		Credentials: stscreds.NewCredentials(sess, "arn:aws:iam::123456789012:role/Admin"),
+	})
+
+	if _, err := credscache.InjectFileCacheProvider(roleSess.Config); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleInvalidateOnExpiredTokenHandler() {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState:       session.SharedConfigEnable,
+		AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := credscache.InjectFileCacheProvider(sess.Config); err != nil {
+		log.Fatal(err)
+	}
+
+	sess.Handlers.Retry.PushBackNamed(credscache.InvalidateOnExpiredTokenHandler)
+}
+
+func ExampleNewSession() {
+	sess, result, err := credscache.NewSession(session.Options{
+		SharedConfigState:       session.SharedConfigEnable,
+		AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !result.Injected {
+		log.Print(result)
+	}
+
+	_ = sts.New(sess)
+}
+
+func ExampleInjectFileCacheProvider_withObserver() {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState:       session.SharedConfigEnable,
+		AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	counters := credscacheutil.NewCounterObserver()
+	counters.Publish("credscache")
+
+	result, err := credscache.InjectFileCacheProvider(sess.Config, func(o *credscache.FileCacheOptions) {
+		o.Observer = credscacheutil.MultiObserver{
+			credscacheutil.NewSlogObserver(slog.Default()),
+			counters,
+		}
 	})
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if !injected {
-		log.Print("unable to inject file cache provider")
+	if !result.Injected {
+		log.Print(result)
 	}
 }
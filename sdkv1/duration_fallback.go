@@ -0,0 +1,80 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/Aton-Kish/aws-credscache-go/internal/credscache"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+)
+
+// retrieve calls the wrapped provider and returns the effective duration of
+// the credentials if it is known. With DurationFallback, an assume role
+// provider whose DurationSeconds is rejected is retried with shorter
+// durations. It keeps a shorter duration for later refreshes only once the
+// duration is accepted, and restores its own duration otherwise.
+func (p *FileCacheProvider) retrieve(ctx context.Context) (credentials.Value, time.Duration, error) {
+	provider, ok := p.provider.(*stscreds.AssumeRoleProvider)
+	if !ok || !p.options.DurationFallback {
+		creds, err := p.provider.RetrieveWithContext(ctx)
+		return creds, 0, err
+	}
+
+	// every attempt needs the MFA code, so ask for it only once
+	if tokenProvider := provider.TokenProvider; tokenProvider != nil {
+		provider.TokenProvider = credscache.MemoizeTokenProvider(tokenProvider)
+		defer func() { provider.TokenProvider = tokenProvider }()
+	}
+
+	original := provider.Duration
+
+	var creds credentials.Value
+	var err error
+	for _, duration := range credscacheutil.AssumeRoleFallbackDurations(original) {
+		provider.Duration = duration
+		creds, err = provider.RetrieveWithContext(ctx)
+		if !isDurationValidationError(err) {
+			break
+		}
+	}
+
+	if err != nil {
+		provider.Duration = original
+		return creds, 0, err
+	}
+
+	return creds, provider.Duration, nil
+}
+
+func isDurationValidationError(err error) bool {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+
+	return credscache.IsDurationValidationError(awsErr.Code(), awsErr.Message())
+}
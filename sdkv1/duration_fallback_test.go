@@ -0,0 +1,225 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMaxSessionDurationServer struct {
+	mu                 sync.Mutex
+	maxSessionDuration int
+	durations          []int
+	tokenCodes         []string
+}
+
+func (s *fakeMaxSessionDurationServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r.ParseForm()
+	w.Header().Set("Content-Type", "text/xml")
+
+	duration, _ := strconv.Atoi(r.Form.Get("DurationSeconds"))
+	s.durations = append(s.durations, duration)
+	if tokenCode := r.Form.Get("TokenCode"); tokenCode != "" {
+		s.tokenCodes = append(s.tokenCodes, tokenCode)
+	}
+	if duration > s.maxSessionDuration {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<ErrorResponse><Error><Type>Sender</Type><Code>ValidationError</Code><Message>The requested DurationSeconds exceeds the MaxSessionDuration set for this role.</Message></Error><RequestId>RequestID</RequestId></ErrorResponse>`)
+		return
+	}
+
+	expiration := time.Now().UTC().Add(time.Duration(duration) * time.Second).Format(time.RFC3339)
+	fmt.Fprintf(w, `<AssumeRoleResponse><AssumeRoleResult><Credentials><AccessKeyId>AssumedAccessKeyID</AccessKeyId><SecretAccessKey>AssumedSecretAccessKey</SecretAccessKey><SessionToken>AssumedSessionToken</SessionToken><Expiration>%s</Expiration></Credentials><AssumedRoleUser><Arn>arn:aws:sts::123456789012:assumed-role/role/session</Arn><AssumedRoleId>AssumedRoleID:session</AssumedRoleId></AssumedRoleUser></AssumeRoleResult><ResponseMetadata><RequestId>RequestID</RequestId></ResponseMetadata></AssumeRoleResponse>`, expiration)
+}
+
+func TestFileCacheProvider_RetrieveWithDurationFallback(t *testing.T) {
+	type args struct {
+		durationFallback   bool
+		duration           time.Duration
+		maxSessionDuration int
+		mfa                bool
+	}
+
+	type expected struct {
+		durations                []int
+		duration                 time.Duration
+		tokenCodes               []string
+		tokenProviderCalls       int
+		effectiveDurationSeconds int64
+		err                      bool
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: fall back to shorter duration",
+			args: args{
+				durationFallback:   true,
+				duration:           time.Duration(3) * time.Hour,
+				maxSessionDuration: 7200,
+			},
+			expected: expected{
+				durations:                []int{10800, 7200},
+				duration:                 time.Duration(2) * time.Hour,
+				effectiveDurationSeconds: 7200,
+				err:                      false,
+			},
+		},
+		{
+			name: "positive case: fall back to shorter duration with MFA",
+			args: args{
+				durationFallback:   true,
+				duration:           time.Duration(3) * time.Hour,
+				maxSessionDuration: 7200,
+				mfa:                true,
+			},
+			expected: expected{
+				durations:                []int{10800, 7200},
+				duration:                 time.Duration(2) * time.Hour,
+				tokenCodes:               []string{"123456", "123456"},
+				tokenProviderCalls:       1,
+				effectiveDurationSeconds: 7200,
+				err:                      false,
+			},
+		},
+		{
+			name: "positive case: requested duration accepted",
+			args: args{
+				durationFallback:   true,
+				duration:           time.Duration(1) * time.Hour,
+				maxSessionDuration: 7200,
+			},
+			expected: expected{
+				durations:                []int{3600},
+				duration:                 time.Duration(1) * time.Hour,
+				effectiveDurationSeconds: 3600,
+				err:                      false,
+			},
+		},
+		{
+			name: "negative case: fallback disabled",
+			args: args{
+				durationFallback:   false,
+				duration:           time.Duration(3) * time.Hour,
+				maxSessionDuration: 7200,
+			},
+			expected: expected{
+				durations: []int{10800},
+				duration:  time.Duration(3) * time.Hour,
+				err:       true,
+			},
+		},
+		{
+			name: "negative case: no acceptable duration",
+			args: args{
+				durationFallback:   true,
+				duration:           time.Duration(3) * time.Hour,
+				maxSessionDuration: 1800,
+			},
+			expected: expected{
+				durations: []int{10800, 7200, 3600},
+				duration:  time.Duration(3) * time.Hour,
+				err:       true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cacheDir := t.TempDir()
+
+			server := &fakeMaxSessionDurationServer{maxSessionDuration: tt.args.maxSessionDuration}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			tokenProviderCalls := 0
+			tokenProvider := func() (string, error) {
+				tokenProviderCalls++
+				return "123456", nil
+			}
+
+			sess := session.Must(session.NewSession(&aws.Config{
+				Region:      aws.String("us-east-1"),
+				Endpoint:    aws.String(ts.URL),
+				Credentials: credentials.NewStaticCredentials("AccessKeyID", "SecretAccessKey", ""),
+				MaxRetries:  aws.Int(0),
+			}))
+			provider := &stscreds.AssumeRoleProvider{
+				Client:   sts.New(sess),
+				RoleARN:  "arn:aws:iam::123456789012:role/role",
+				Duration: tt.args.duration,
+			}
+			if tt.args.mfa {
+				provider.SerialNumber = aws.String("arn:aws:iam::123456789012:mfa/user")
+				provider.TokenProvider = tokenProvider
+			}
+
+			key, err := AssumeRoleCacheKey(provider)
+			assert.NoError(t, err)
+
+			fileCacheProvider := NewFileCacheProvider(provider, key, func(o *FileCacheOptions) {
+				o.FileCacheDir = cacheDir
+				o.DurationFallback = tt.args.durationFallback
+			})
+
+			// Act
+			_, err = fileCacheProvider.RetrieveWithContext(context.Background())
+
+			// Assert
+			assert.Equal(t, tt.expected.durations, server.durations)
+			assert.Equal(t, tt.expected.tokenCodes, server.tokenCodes)
+			assert.Equal(t, tt.expected.tokenProviderCalls, tokenProviderCalls)
+			assert.Equal(t, tt.expected.duration, provider.Duration)
+			if !tt.expected.err {
+				assert.NoError(t, err)
+
+				cache := new(credscacheutil.FileCache)
+				assert.NoError(t, cache.Load(filepath.Join(cacheDir, fmt.Sprintf("%s.json", key))))
+				assert.Equal(t, tt.expected.effectiveDurationSeconds, cache.EffectiveDurationSeconds)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -25,10 +25,15 @@ import (
 )
 
 var (
-	ErrNilPointer = credscache.ErrNilPointer
+	ErrAmbiguousProfile   = credscache.ErrAmbiguousProfile
+	ErrNilPointer         = credscache.ErrNilPointer
+	ErrTokenProviderUnset = credscache.ErrTokenProviderUnset
+	ErrUnsupportedClient  = credscache.ErrUnsupportedClient
+	ErrUnsupportedCommand = credscache.ErrUnsupportedCommand
 )
 
 type (
 	FileCacheProviderError = credscache.FileCacheProviderError
 	InjectionError         = credscache.InjectionError
+	InsecureCacheError     = credscache.InsecureCacheError
 )
@@ -27,9 +27,9 @@ import (
 	"github.com/aws/aws-sdk-go/aws/credentials"
 )
 
-func LoadCredentials(path string) (*credentials.Value, time.Time, error) {
+func LoadCredentials(path string, optFns ...func(o *credscacheutil.FileOptions)) (*credentials.Value, time.Time, error) {
 	cache := new(credscacheutil.FileCache)
-	if err := cache.Load(path); err != nil {
+	if err := cache.Load(path, optFns...); err != nil {
 		return nil, time.Time{}, err
 	}
 
@@ -42,7 +42,11 @@ func LoadCredentials(path string) (*credentials.Value, time.Time, error) {
 	return creds, cache.Credentials.Expires, nil
 }
 
-func StoreCredentials(path string, creds *credentials.Value, expires time.Time) error {
+func StoreCredentials(path string, creds *credentials.Value, expires time.Time, optFns ...func(o *credscacheutil.FileOptions)) error {
+	return storeCredentials(path, creds, expires, 0, optFns...)
+}
+
+func storeCredentials(path string, creds *credentials.Value, expires time.Time, duration time.Duration, optFns ...func(o *credscacheutil.FileOptions)) error {
 	cache := &credscacheutil.FileCache{
 		Credentials: credscacheutil.CachedCredentials{
 			AccessKeyID:     creds.AccessKeyID,
@@ -50,9 +54,10 @@ func StoreCredentials(path string, creds *credentials.Value, expires time.Time)
 			SessionToken:    creds.SessionToken,
 			Expires:         expires,
 		},
+		EffectiveDurationSeconds: int64(duration / time.Second),
 	}
 
-	if err := cache.Store(path); err != nil {
+	if err := cache.Store(path, optFns...); err != nil {
 		return err
 	}
 
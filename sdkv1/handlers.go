@@ -0,0 +1,103 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Aton-Kish/aws-credscache-go/internal/credscache"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	InvalidateOnExpiredTokenHandlerName = "credscache.InvalidateOnExpiredTokenHandler"
+	InvalidateOnMinLifetimeHandlerName  = "credscache.InvalidateOnMinLifetimeHandler"
+)
+
+type invalidatedKey struct{}
+
+var InvalidateOnExpiredTokenHandler = request.NamedHandler{
+	Name: InvalidateOnExpiredTokenHandlerName,
+	Fn:   invalidateOnExpiredToken,
+}
+
+func invalidateOnExpiredToken(r *request.Request) {
+	if !isExpiredTokenError(r.Error) || r.Config.Credentials == nil {
+		return
+	}
+
+	// invalidate only once so that revoked credentials do not loop until the
+	// retries are exhausted
+	if invalidated, _ := r.Context().Value(invalidatedKey{}).(bool); invalidated {
+		return
+	}
+
+	fileCacheProvider, ok := LookupFileCacheProvider(&r.Config)
+	if !ok {
+		return
+	}
+
+	if err := fileCacheProvider.Invalidate(r.Context()); err != nil {
+		return
+	}
+
+	r.Config.Credentials.Expire()
+	r.Retryable = aws.Bool(true)
+	r.SetContext(context.WithValue(r.Context(), invalidatedKey{}, true))
+}
+
+var InvalidateOnMinLifetimeHandler = request.NamedHandler{
+	Name: InvalidateOnMinLifetimeHandlerName,
+	Fn:   invalidateOnMinLifetime,
+}
+
+// invalidateOnMinLifetime expires the credentials in memory when they expire
+// within the minimum lifetime of the request context, so that signing
+// retrieves them through the file cache provider with the same minimum
+// lifetime.
+func invalidateOnMinLifetime(r *request.Request) {
+	d, ok := credscache.MinLifetime(r.Context())
+	if !ok || r.Config.Credentials == nil {
+		return
+	}
+
+	expires, err := r.Config.Credentials.ExpiresAt()
+	if err != nil || expires.IsZero() {
+		return
+	}
+
+	if expires.Before(time.Now().Add(d)) {
+		r.Config.Credentials.Expire()
+	}
+}
+
+func isExpiredTokenError(err error) bool {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+
+	return credscache.IsExpiredTokenErrorCode(awsErr.Code())
+}
@@ -0,0 +1,348 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	mock_credscache "github.com/Aton-Kish/aws-credscache-go/internal/mock/github.com/Aton-Kish/aws-credscache-go/sdkv1"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSTSServer struct {
+	mu           sync.Mutex
+	code         string
+	accessKeyIDs []string
+}
+
+var credentialPattern = regexp.MustCompile(`Credential=([^/]+)/`)
+
+func (s *fakeSTSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accessKeyID := ""
+	if m := credentialPattern.FindStringSubmatch(r.Header.Get("Authorization")); m != nil {
+		accessKeyID = m[1]
+	}
+	s.accessKeyIDs = append(s.accessKeyIDs, accessKeyID)
+
+	w.Header().Set("Content-Type", "text/xml")
+
+	if accessKeyID == "CachedAccessKeyID" {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `<ErrorResponse><Error><Type>Sender</Type><Code>%s</Code><Message>error</Message></Error><RequestId>RequestID</RequestId></ErrorResponse>`, s.code)
+		return
+	}
+
+	fmt.Fprint(w, `<GetCallerIdentityResponse><GetCallerIdentityResult><Arn>arn:aws:iam::123456789012:user/gopher</Arn><UserId>UserID</UserId><Account>123456789012</Account></GetCallerIdentityResult><ResponseMetadata><RequestId>RequestID</RequestId></ResponseMetadata></GetCallerIdentityResponse>`)
+}
+
+func TestInvalidateOnExpiredTokenHandler(t *testing.T) {
+	expiresIn15Minutes := time.Now().UTC().Add(time.Duration(15) * time.Minute)
+	retrievedCreds := credentials.Value{
+		AccessKeyID:     "RetrievedAccessKeyID",
+		SecretAccessKey: "RetrievedSecretAccessKey",
+		SessionToken:    "RetrievedSessionToken",
+		ProviderName:    "TestProvider",
+	}
+
+	type fields struct {
+		installed bool
+	}
+
+	type args struct {
+		code string
+	}
+
+	type expected struct {
+		accessKeyIDs  []string
+		retrieveTimes int
+		err           string
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: ExpiredToken",
+			fields: fields{
+				installed: true,
+			},
+			args: args{
+				code: "ExpiredToken",
+			},
+			expected: expected{
+				accessKeyIDs:  []string{"CachedAccessKeyID", "RetrievedAccessKeyID"},
+				retrieveTimes: 1,
+				err:           "",
+			},
+		},
+		{
+			name: "positive case: InvalidClientTokenId",
+			fields: fields{
+				installed: true,
+			},
+			args: args{
+				code: "InvalidClientTokenId",
+			},
+			expected: expected{
+				accessKeyIDs:  []string{"CachedAccessKeyID", "RetrievedAccessKeyID"},
+				retrieveTimes: 1,
+				err:           "",
+			},
+		},
+		{
+			name: "negative case: AccessDenied",
+			fields: fields{
+				installed: true,
+			},
+			args: args{
+				code: "AccessDenied",
+			},
+			expected: expected{
+				accessKeyIDs:  []string{"CachedAccessKeyID"},
+				retrieveTimes: 0,
+				err:           "AccessDenied",
+			},
+		},
+		{
+			name: "negative case: not installed",
+			fields: fields{
+				installed: false,
+			},
+			args: args{
+				code: "ExpiredToken",
+			},
+			expected: expected{
+				accessKeyIDs:  []string{"CachedAccessKeyID", "CachedAccessKeyID"},
+				retrieveTimes: 0,
+				err:           "ExpiredToken",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cachedDir := t.TempDir()
+			StoreCredentials(filepath.Join(cachedDir, fmt.Sprintf("%s.json", "key")), &credentials.Value{AccessKeyID: "CachedAccessKeyID", SecretAccessKey: "CachedSecretAccessKey"}, expiresIn15Minutes)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProviderWithContext := mock_credscache.NewMockexpireProviderWithContext(ctrl)
+			mockProviderWithContext.
+				EXPECT().
+				RetrieveWithContext(gomock.Any()).
+				Return(retrievedCreds, nil).
+				Times(tt.expected.retrieveTimes)
+			mockProviderWithContext.
+				EXPECT().
+				ExpiresAt().
+				Return(expiresIn15Minutes).
+				Times(tt.expected.retrieveTimes)
+
+			server := &fakeSTSServer{code: tt.args.code}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			sess := session.Must(session.NewSession(&aws.Config{
+				Credentials: credentials.NewCredentials(NewFileCacheProvider(mockProviderWithContext, "key", func(o *FileCacheOptions) {
+					o.FileCacheDir = cachedDir
+				})),
+				Endpoint:   aws.String(ts.URL),
+				Region:     aws.String("us-east-1"),
+				MaxRetries: aws.Int(1),
+			}))
+			if tt.fields.installed {
+				sess.Handlers.Retry.PushBackNamed(InvalidateOnExpiredTokenHandler)
+			}
+
+			client := sts.New(sess)
+
+			// Act
+			_, err := client.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+
+			// Assert
+			assert.Equal(t, tt.expected.accessKeyIDs, server.accessKeyIDs)
+			if tt.expected.err == "" {
+				assert.NoError(t, err)
+			} else {
+				var awsErr awserr.Error
+				assert.ErrorAs(t, err, &awsErr)
+				assert.Equal(t, tt.expected.err, awsErr.Code())
+			}
+		})
+	}
+}
+
+func TestInvalidateOnMinLifetimeHandler(t *testing.T) {
+	expiresIn15Minutes := time.Now().UTC().Add(time.Duration(15) * time.Minute)
+	expiresIn60Minutes := time.Now().UTC().Add(time.Duration(60) * time.Minute)
+	retrievedCreds := credentials.Value{
+		AccessKeyID:     "RetrievedAccessKeyID",
+		SecretAccessKey: "RetrievedSecretAccessKey",
+		SessionToken:    "RetrievedSessionToken",
+		ProviderName:    "TestProvider",
+	}
+
+	type fields struct {
+		installed bool
+	}
+
+	type args struct {
+		minLifetime time.Duration
+	}
+
+	type expected struct {
+		accessKeyID   string
+		retrieveTimes int
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: credentials outlive the minimum lifetime",
+			fields: fields{
+				installed: true,
+			},
+			args: args{
+				minLifetime: time.Duration(10) * time.Minute,
+			},
+			expected: expected{
+				accessKeyID:   "CachedAccessKeyID",
+				retrieveTimes: 0,
+			},
+		},
+		{
+			name: "positive case: credentials expire within the minimum lifetime",
+			fields: fields{
+				installed: true,
+			},
+			args: args{
+				minLifetime: time.Duration(30) * time.Minute,
+			},
+			expected: expected{
+				accessKeyID:   "RetrievedAccessKeyID",
+				retrieveTimes: 1,
+			},
+		},
+		{
+			name: "positive case: without minimum lifetime",
+			fields: fields{
+				installed: true,
+			},
+			args: args{
+				minLifetime: 0,
+			},
+			expected: expected{
+				accessKeyID:   "CachedAccessKeyID",
+				retrieveTimes: 0,
+			},
+		},
+		{
+			name: "negative case: not installed",
+			fields: fields{
+				installed: false,
+			},
+			args: args{
+				minLifetime: time.Duration(30) * time.Minute,
+			},
+			expected: expected{
+				accessKeyID:   "CachedAccessKeyID",
+				retrieveTimes: 0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cachedDir := t.TempDir()
+			StoreCredentials(filepath.Join(cachedDir, fmt.Sprintf("%s.json", "key")), &credentials.Value{AccessKeyID: "CachedAccessKeyID", SecretAccessKey: "CachedSecretAccessKey"}, expiresIn15Minutes)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProviderWithContext := mock_credscache.NewMockexpireProviderWithContext(ctrl)
+			mockProviderWithContext.
+				EXPECT().
+				RetrieveWithContext(gomock.Any()).
+				Return(retrievedCreds, nil).
+				Times(tt.expected.retrieveTimes)
+			mockProviderWithContext.
+				EXPECT().
+				ExpiresAt().
+				Return(expiresIn60Minutes).
+				Times(tt.expected.retrieveTimes)
+
+			sess := session.Must(session.NewSession(&aws.Config{
+				Credentials: credentials.NewCredentials(NewFileCacheProvider(mockProviderWithContext, "key", func(o *FileCacheOptions) {
+					o.FileCacheDir = cachedDir
+				})),
+				Region: aws.String("us-east-1"),
+			}))
+			if tt.fields.installed {
+				sess.Handlers.Sign.PushFrontNamed(InvalidateOnMinLifetimeHandler)
+			}
+			sess.Config.Credentials.Get()
+
+			ctx := context.Background()
+			if tt.args.minLifetime > 0 {
+				ctx = WithMinLifetime(ctx, tt.args.minLifetime)
+			}
+
+			req, _ := sts.New(sess).GetCallerIdentityRequest(&sts.GetCallerIdentityInput{})
+			req.SetContext(ctx)
+
+			// Act
+			err := req.Sign()
+
+			// Assert
+			assert.NoError(t, err)
+			m := credentialPattern.FindStringSubmatch(req.HTTPRequest.Header.Get("Authorization"))
+			assert.NotNil(t, m)
+			assert.Equal(t, tt.expected.accessKeyID, m[1])
+		})
+	}
+}
@@ -21,31 +21,187 @@
 package credscache
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/Aton-Kish/aws-credscache-go/internal/credscache"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/processcreds"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 )
 
-func InjectFileCacheProvider(cfg *aws.Config, optFns ...func(o *FileCacheOptions)) (bool, error) {
+type (
+	SkipReason      = credscache.SkipReason
+	InjectionResult = credscache.InjectionResult
+)
+
+const (
+	SkipReasonNone                = credscache.SkipReasonNone
+	SkipReasonNoCredentials       = credscache.SkipReasonNoCredentials
+	SkipReasonUnsupportedProvider = credscache.SkipReasonUnsupportedProvider
+	SkipReasonUnsupportedCommand  = credscache.SkipReasonUnsupportedCommand
+	SkipReasonAlreadyInjected     = credscache.SkipReasonAlreadyInjected
+	SkipReasonAmbiguousProfile    = credscache.SkipReasonAmbiguousProfile
+)
+
+type processProviderWithContext struct {
+	*processcreds.ProcessProvider
+}
+
+func (p *processProviderWithContext) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	return p.Retrieve()
+}
+
+func InjectFileCacheProvider(cfg *aws.Config, optFns ...func(o *FileCacheOptions)) (*InjectionResult, error) {
+	result := &InjectionResult{}
+
+	if cfg.Credentials == nil {
+		result.Reason = SkipReasonNoCredentials
+		return result, nil
+	}
+
 	credsAccessor, err := NewCredentialsUnsafeAccessor(cfg.Credentials)
 	if err != nil {
 		err = &InjectionError{Err: err}
-		return false, err
+		return result, err
 	}
 
-	provider := credsAccessor.Provider()
-	assumeRoleProvider, ok := provider.(*stscreds.AssumeRoleProvider)
-	if !ok {
-		return false, nil
+	o := FileCacheOptions{}
+
+	for _, fn := range optFns {
+		fn(&o)
 	}
 
-	key, err := AssumeRoleCacheKey(assumeRoleProvider)
+	profile := credscacheutil.ResolveProfileName(o.Profile)
+	provider := credsAccessor.Provider()
+	result.ProviderChain = []string{fmt.Sprintf("%T", cfg.Credentials), fmt.Sprintf("%T", provider)}
+
+	var key string
+	var sourceKey *sourceAccessKeyCacheKey
+	var target credentials.ProviderWithContext
+	switch provider := provider.(type) {
+	case *FileCacheProvider:
+		result.Reason = SkipReasonAlreadyInjected
+		return result, nil
+	case *stscreds.AssumeRoleProvider:
+		var sharedProfile *credscacheutil.SharedProfile
+		sharedProfile, err = findSharedProfile(cfg, profile, assumeRoleProfileMatcher(provider), equivalentAssumeRoleProfiles)
+		if errors.Is(err, ErrAmbiguousProfile) {
+			result.Reason = SkipReasonAmbiguousProfile
+			return result, nil
+		}
+		if err != nil {
+			break
+		}
+		if sharedProfile != nil {
+			profile = sharedProfile.Name
+		}
+
+		// a provider built without a profile keys on its own duration
+		keyOptions := func(ko *AssumeRoleCacheKeyOptions) {
+			ko.SharedProfile = sharedProfile
+			ko.STSEndpoint = o.STSEndpointCacheKey
+		}
+		if o.SourceAccessKeyCacheKey {
+			sourceKey, err = assumeRoleSourceAccessKeyCacheKey(provider, keyOptions)
+		} else {
+			key, err = AssumeRoleCacheKey(provider, keyOptions)
+		}
+		target = provider
+	case *SessionTokenProvider:
+		if o.SourceAccessKeyCacheKey {
+			sourceKey, err = sessionTokenSourceAccessKeyCacheKey(provider, profile)
+		} else {
+			key, err = SessionTokenCacheKey(provider, profile)
+		}
+		target = provider
+	case *processcreds.ProcessProvider:
+		var sharedProfile *credscacheutil.SharedProfile
+		sharedProfile, err = findSharedProfile(cfg, profile, processProfileMatcher(provider), equivalentProcessProfiles)
+		if errors.Is(err, ErrAmbiguousProfile) {
+			result.Reason = SkipReasonAmbiguousProfile
+			return result, nil
+		}
+		if err != nil {
+			break
+		}
+		if sharedProfile != nil {
+			profile = sharedProfile.Name
+		}
+
+		key, err = ProcessCacheKey(provider, profile)
+		if errors.Is(err, ErrUnsupportedCommand) {
+			result.Reason = SkipReasonUnsupportedCommand
+			return result, nil
+		}
+		target = &processProviderWithContext{ProcessProvider: provider}
+	default:
+		result.Reason = SkipReasonUnsupportedProvider
+		return result, nil
+	}
 	if err != nil {
 		err = &InjectionError{Err: err}
-		return false, err
+		return result, err
+	}
+	fileCacheProvider := NewFileCacheProvider(target, key, optFns...)
+	fileCacheProvider.sourceKey = sourceKey
+	// the path is informational, so the error is left to Retrieve
+	path, _ := fileCacheProvider.path()
+
+	// the MFA wiring comes before the provider is swapped, so that a failure
+	// leaves the config as it was
+	switch provider := provider.(type) {
+	case *stscreds.AssumeRoleProvider:
+		injected := false
+		if o.MFASession {
+			injected, err = injectMFASession(provider, sourceProfile(profile), fileCacheProvider.options)
+			if err != nil {
+				err = &InjectionError{Err: err}
+				return result, err
+			}
+		}
+		if !injected {
+			injectMFATokenProvider(provider, profile, path, o.MFAPrompter)
+		}
+	case *SessionTokenProvider:
+		provider.injectMFATokenProvider(profile, path, o.MFAPrompter)
 	}
 
-	fileCacheProvider := NewFileCacheProvider(assumeRoleProvider, key, optFns...)
 	credsAccessor.SetProvider(fileCacheProvider)
 
-	return true, nil
+	result.Injected = true
+	result.Wrapped = fmt.Sprintf("%T", provider)
+	result.CacheKey = key
+	result.Path = path
+
+	return result, nil
+}
+
+// injectMFATokenProvider wires a prompt into an assume role provider with an
+// MFA serial number but no token provider. The prompt only runs when the file
+// cache misses and the provider actually calls AssumeRole.
+func injectMFATokenProvider(provider *stscreds.AssumeRoleProvider, profile string, path string, prompter credscacheutil.MFAPrompter) {
+	serialNumber := aws.StringValue(provider.SerialNumber)
+	if !credscacheutil.NeedsMFATokenProvider(serialNumber, provider.TokenProvider) {
+		return
+	}
+
+	provider.TokenProvider = credscacheutil.NewMFATokenProvider(prompter, credscacheutil.MFAPrompt{
+		RoleARN:      provider.RoleARN,
+		SerialNumber: serialNumber,
+		Profile:      profile,
+		CachePath:    path,
+	})
+}
+
+func LookupFileCacheProvider(cfg *aws.Config) (*FileCacheProvider, bool) {
+	credsAccessor, err := NewCredentialsUnsafeAccessor(cfg.Credentials)
+	if err != nil {
+		return nil, false
+	}
+
+	fileCacheProvider, ok := credsAccessor.Provider().(*FileCacheProvider)
+	return fileCacheProvider, ok
 }
@@ -0,0 +1,116 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+type sessionTokenAssumeRoler interface {
+	GetSessionTokenAPIClient
+	AssumeRoleWithContext(ctx aws.Context, input *sts.AssumeRoleInput, opts ...request.Option) (*sts.AssumeRoleOutput, error)
+}
+
+// assumeRoleClientWithCredentials signs AssumeRole with the MFA session
+// instead of the credentials the client was created with.
+type assumeRoleClientWithCredentials struct {
+	client      sessionTokenAssumeRoler
+	credentials *credentials.Credentials
+}
+
+var _ interface {
+	stscreds.AssumeRoler
+} = &assumeRoleClientWithCredentials{}
+
+func (c *assumeRoleClientWithCredentials) AssumeRole(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+	return c.AssumeRoleWithContext(aws.BackgroundContext(), input)
+}
+
+func (c *assumeRoleClientWithCredentials) AssumeRoleWithContext(ctx aws.Context, input *sts.AssumeRoleInput, opts ...request.Option) (*sts.AssumeRoleOutput, error) {
+	opts = append(opts, func(r *request.Request) {
+		r.Config.Credentials = c.credentials
+	})
+
+	return c.client.AssumeRoleWithContext(ctx, input, opts...)
+}
+
+// injectMFASession makes an assume role provider with an MFA serial number
+// assume the role with a cached GetSessionToken session of the source profile,
+// so that roles sharing the source profile need a single MFA code. It returns
+// false when the client cannot call GetSessionToken.
+func injectMFASession(provider *stscreds.AssumeRoleProvider, profile string, o FileCacheOptions) (bool, error) {
+	client, ok := provider.Client.(sessionTokenAssumeRoler)
+	if !ok || provider.SerialNumber == nil || provider.TokenCode != nil {
+		return false, nil
+	}
+
+	sessionProvider := NewSessionTokenProviderWithClient(client, func(p *SessionTokenProvider) {
+		p.SerialNumber = provider.SerialNumber
+		p.TokenProvider = provider.TokenProvider
+		p.Duration = o.MFASessionDuration
+	})
+
+	var key string
+	var sourceKey *sourceAccessKeyCacheKey
+	var err error
+	if o.SourceAccessKeyCacheKey {
+		sourceKey, err = sessionTokenSourceAccessKeyCacheKey(sessionProvider, profile)
+	} else {
+		key, err = SessionTokenCacheKey(sessionProvider, profile)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	fileCacheProvider := NewFileCacheProvider(sessionProvider, key, func(fo *FileCacheOptions) {
+		*fo = o
+	})
+	fileCacheProvider.sourceKey = sourceKey
+
+	// the path is informational, so the error is left to Retrieve
+	path, _ := fileCacheProvider.path()
+	sessionProvider.injectMFATokenProvider(profile, path, o.MFAPrompter)
+
+	provider.Client = &assumeRoleClientWithCredentials{
+		client:      client,
+		credentials: credentials.NewCredentials(fileCacheProvider),
+	}
+	provider.SerialNumber = nil
+	provider.TokenProvider = nil
+
+	return true, nil
+}
+
+// sourceProfile returns the source_profile of a profile, falling back to the
+// profile itself.
+func sourceProfile(profile string) string {
+	sharedProfile, err := credscacheutil.LoadSharedProfile(profile)
+	if err != nil || sharedProfile.SourceProfileName == "" {
+		return profile
+	}
+
+	return sharedProfile.SourceProfileName
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInjectFileCacheProvider_MFASession(t *testing.T) {
+	type args struct {
+		mfaSession bool
+	}
+
+	type expected struct {
+		prompts              []*credscacheutil.MFAPrompt
+		sessionTokenCalls    int
+		assumeRoleAccessKeys []string
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: roles share MFA session",
+			args: args{
+				mfaSession: true,
+			},
+			expected: expected{
+				prompts: []*credscacheutil.MFAPrompt{
+					{SerialNumber: "arn:aws:iam::123456789012:mfa/user", Profile: "default"},
+				},
+				sessionTokenCalls:    1,
+				assumeRoleAccessKeys: []string{"SessionAccessKeyID", "SessionAccessKeyID"},
+			},
+		},
+		{
+			name: "positive case: roles prompt separately without MFA session",
+			args: args{
+				mfaSession: false,
+			},
+			expected: expected{
+				prompts: []*credscacheutil.MFAPrompt{
+					{RoleARN: "arn:aws:iam::123456789012:role/role-a", SerialNumber: "arn:aws:iam::123456789012:mfa/user", Profile: "role-a"},
+					{RoleARN: "arn:aws:iam::123456789012:role/role-b", SerialNumber: "arn:aws:iam::123456789012:mfa/user", Profile: "role-b"},
+				},
+				sessionTokenCalls:    0,
+				assumeRoleAccessKeys: []string{"AccessKeyID", "AccessKeyID"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tempDir := t.TempDir()
+			cacheDir := filepath.Join(tempDir, "cache")
+			t.Setenv("AWS_CONFIG_FILE", filepath.Join(tempDir, "config"))
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(tempDir, "credentials"))
+			t.Setenv("AWS_ACCESS_KEY_ID", "")
+			t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+			t.Setenv("AWS_PROFILE", "")
+			os.WriteFile(filepath.Join(tempDir, "config"), []byte("[default]\nregion = us-east-1\n\n"+
+				"[profile role-a]\nregion = us-east-1\nrole_arn = arn:aws:iam::123456789012:role/role-a\nsource_profile = default\nmfa_serial = arn:aws:iam::123456789012:mfa/user\n\n"+
+				"[profile role-b]\nregion = us-east-1\nrole_arn = arn:aws:iam::123456789012:role/role-b\nsource_profile = default\nmfa_serial = arn:aws:iam::123456789012:mfa/user\n"), 0600)
+			os.WriteFile(filepath.Join(tempDir, "credentials"), []byte("[default]\naws_access_key_id = AccessKeyID\naws_secret_access_key = SecretAccessKey\n"), 0600)
+
+			server := &fakeAssumeRoleServer{}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			var prompts []*credscacheutil.MFAPrompt
+			prompter := func(prompt *credscacheutil.MFAPrompt) (string, error) {
+				prompts = append(prompts, prompt)
+				return "123456", nil
+			}
+
+			// Act
+			for _, profile := range []string{"role-a", "role-b"} {
+				sess, err := session.NewSessionWithOptions(session.Options{
+					Profile:                 profile,
+					SharedConfigState:       session.SharedConfigEnable,
+					AssumeRoleTokenProvider: credscacheutil.DeferredMFATokenProvider,
+					Config:                  aws.Config{Endpoint: aws.String(ts.URL)},
+				})
+				assert.NoError(t, err)
+
+				_, err = InjectFileCacheProvider(sess.Config, func(o *FileCacheOptions) {
+					o.FileCacheDir = cacheDir
+					o.Profile = profile
+					o.MFASession = tt.args.mfaSession
+					o.MFAPrompter = prompter
+				})
+				assert.NoError(t, err)
+
+				_, err = sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			for _, prompt := range prompts {
+				prompt.CachePath = ""
+			}
+			assert.Equal(t, tt.expected.prompts, prompts)
+			assert.Equal(t, tt.expected.sessionTokenCalls, server.sessionTokenCalls)
+			assert.Equal(t, tt.expected.assumeRoleAccessKeys, server.assumeRoleAccessKeys)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+const (
+	InjectFileCacheProviderHandlerName = "credscache.InjectFileCacheProviderHandler"
+)
+
+var injectMu sync.Mutex
+
+func NewSession(opts session.Options, optFns ...func(o *FileCacheOptions)) (*session.Session, *InjectionResult, error) {
+	sess, err := session.NewSessionWithOptions(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.Profile != "" {
+		optFns = append([]func(o *FileCacheOptions){func(o *FileCacheOptions) { o.Profile = opts.Profile }}, optFns...)
+	}
+
+	result, err := InjectFileCacheProvider(sess.Config, optFns...)
+	if err != nil {
+		return nil, result, err
+	}
+
+	// credentials overridden by session.Copy or client configs are injected
+	// lazily, right before the request is signed
+	handled := &sync.Map{}
+	handled.Store(sess.Config.Credentials, struct{}{})
+	sess.Handlers.Sign.PushFrontNamed(request.NamedHandler{
+		Name: InjectFileCacheProviderHandlerName,
+		Fn: func(r *request.Request) {
+			injectFileCacheProvider(r, handled, optFns...)
+		},
+	})
+
+	return sess, result, nil
+}
+
+// injectFileCacheProvider injects the file cache provider into the
+// credentials of the request once, and remembers them in handled whatever the
+// result is, so later requests skip the lock.
+func injectFileCacheProvider(r *request.Request, handled *sync.Map, optFns ...func(o *FileCacheOptions)) {
+	creds := r.Config.Credentials
+	if creds == nil {
+		return
+	}
+
+	if _, ok := handled.Load(creds); ok {
+		return
+	}
+
+	injectMu.Lock()
+	defer injectMu.Unlock()
+
+	if _, ok := handled.Load(creds); ok {
+		return
+	}
+	defer handled.Store(creds, struct{}{})
+
+	// a cache is an optimization, so the request goes on without it
+	if _, err := InjectFileCacheProvider(&r.Config, optFns...); err != nil && r.Config.LogLevel.AtLeast(aws.LogDebug) && r.Config.Logger != nil {
+		r.Config.Logger.Log("unable to inject file cache provider,", err)
+	}
+}
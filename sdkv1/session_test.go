@@ -0,0 +1,256 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAssumeRoleServer struct {
+	mu                   sync.Mutex
+	assumeRoleCalls      int
+	assumeRoleAccessKeys []string
+	sessionTokenCalls    int
+}
+
+func (s *fakeAssumeRoleServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r.ParseForm()
+	w.Header().Set("Content-Type", "text/xml")
+
+	switch r.Form.Get("Action") {
+	case "AssumeRole":
+		s.assumeRoleCalls++
+		s.assumeRoleAccessKeys = append(s.assumeRoleAccessKeys, accessKeyFromAuthorization(r.Header.Get("Authorization")))
+		expiration := time.Now().UTC().Add(time.Duration(1) * time.Hour).Format(time.RFC3339)
+		fmt.Fprintf(w, `<AssumeRoleResponse><AssumeRoleResult><Credentials><AccessKeyId>AssumedAccessKeyID</AccessKeyId><SecretAccessKey>AssumedSecretAccessKey</SecretAccessKey><SessionToken>AssumedSessionToken</SessionToken><Expiration>%s</Expiration></Credentials><AssumedRoleUser><Arn>arn:aws:sts::123456789012:assumed-role/role/session</Arn><AssumedRoleId>AssumedRoleID:session</AssumedRoleId></AssumedRoleUser></AssumeRoleResult><ResponseMetadata><RequestId>RequestID</RequestId></ResponseMetadata></AssumeRoleResponse>`, expiration)
+	case "GetSessionToken":
+		s.sessionTokenCalls++
+		expiration := time.Now().UTC().Add(time.Duration(12) * time.Hour).Format(time.RFC3339)
+		fmt.Fprintf(w, `<GetSessionTokenResponse><GetSessionTokenResult><Credentials><AccessKeyId>SessionAccessKeyID</AccessKeyId><SecretAccessKey>SessionSecretAccessKey</SecretAccessKey><SessionToken>SessionSessionToken</SessionToken><Expiration>%s</Expiration></Credentials></GetSessionTokenResult><ResponseMetadata><RequestId>RequestID</RequestId></ResponseMetadata></GetSessionTokenResponse>`, expiration)
+	default:
+		fmt.Fprint(w, `<GetCallerIdentityResponse><GetCallerIdentityResult><Arn>arn:aws:iam::123456789012:user/gopher</Arn><UserId>UserID</UserId><Account>123456789012</Account></GetCallerIdentityResult><ResponseMetadata><RequestId>RequestID</RequestId></ResponseMetadata></GetCallerIdentityResponse>`)
+	}
+}
+
+func accessKeyFromAuthorization(authorization string) string {
+	_, credential, _ := strings.Cut(authorization, "Credential=")
+	accessKey, _, _ := strings.Cut(credential, "/")
+	return accessKey
+}
+
+func TestNewSession(t *testing.T) {
+	type args struct {
+		profile string
+		copy    bool
+	}
+
+	type expected struct {
+		injected        bool
+		reason          SkipReason
+		assumeRoleCalls int
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: assume role profile",
+			args: args{
+				profile: "role",
+				copy:    false,
+			},
+			expected: expected{
+				injected:        true,
+				reason:          SkipReasonNone,
+				assumeRoleCalls: 1,
+			},
+		},
+		{
+			name: "positive case: assume role credentials of copied session",
+			args: args{
+				profile: "default",
+				copy:    true,
+			},
+			expected: expected{
+				injected:        false,
+				reason:          SkipReasonUnsupportedProvider,
+				assumeRoleCalls: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tempDir := t.TempDir()
+			cacheDir := filepath.Join(tempDir, "cache")
+			t.Setenv("AWS_CONFIG_FILE", filepath.Join(tempDir, "config"))
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(tempDir, "credentials"))
+			t.Setenv("AWS_ACCESS_KEY_ID", "")
+			t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+			t.Setenv("AWS_PROFILE", "")
+			os.WriteFile(filepath.Join(tempDir, "config"), []byte("[default]\nregion = us-east-1\n\n[profile role]\nregion = us-east-1\nrole_arn = arn:aws:iam::123456789012:role/role\nsource_profile = default\n"), 0600)
+			os.WriteFile(filepath.Join(tempDir, "credentials"), []byte("[default]\naws_access_key_id = AccessKeyID\naws_secret_access_key = SecretAccessKey\n"), 0600)
+
+			server := &fakeAssumeRoleServer{}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			// Act
+			sess, actual, err := NewSession(session.Options{
+				Profile:           tt.args.profile,
+				SharedConfigState: session.SharedConfigEnable,
+				Config:            aws.Config{Endpoint: aws.String(ts.URL)},
+			}, func(o *FileCacheOptions) {
+				o.FileCacheDir = cacheDir
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.injected, actual.Injected)
+			assert.Equal(t, tt.expected.reason, actual.Reason)
+
+			if tt.args.copy {
+				sess = sess.Copy(&aws.Config{Credentials: stscreds.NewCredentials(sess, "arn:aws:iam::123456789012:role/other")})
+			}
+
+			for i := 0; i < 2; i++ {
+				client := sts.New(sess)
+				_, err = client.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expected.assumeRoleCalls, server.assumeRoleCalls)
+
+			credsAccessor, _ := NewCredentialsUnsafeAccessor(sess.Config.Credentials)
+			assert.IsType(t, &FileCacheProvider{}, credsAccessor.Provider())
+
+			entries, _ := os.ReadDir(cacheDir)
+			assert.Len(t, entries, 1)
+
+			// the cache file is shared with new sessions
+			other, _, err := NewSession(session.Options{
+				Profile:           tt.args.profile,
+				SharedConfigState: session.SharedConfigEnable,
+				Config:            aws.Config{Endpoint: aws.String(ts.URL)},
+			}, func(o *FileCacheOptions) {
+				o.FileCacheDir = cacheDir
+			})
+			assert.NoError(t, err)
+			if tt.args.copy {
+				other = other.Copy(&aws.Config{Credentials: stscreds.NewCredentials(other, "arn:aws:iam::123456789012:role/other")})
+			}
+
+			_, err = sts.New(other).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.assumeRoleCalls, server.assumeRoleCalls)
+		})
+	}
+}
+
+func TestInjectFileCacheProviderHandler(t *testing.T) {
+	type args struct {
+		handled bool
+	}
+
+	type expected struct {
+		injected bool
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: credentials not handled yet",
+			args: args{
+				handled: false,
+			},
+			expected: expected{
+				injected: true,
+			},
+		},
+		{
+			name: "positive case: credentials already handled",
+			args: args{
+				handled: true,
+			},
+			expected: expected{
+				injected: false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tempDir := t.TempDir()
+			t.Setenv("AWS_CONFIG_FILE", filepath.Join(tempDir, "config"))
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(tempDir, "credentials"))
+
+			sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")}))
+			creds := stscreds.NewCredentials(sess, "arn:aws:iam::123456789012:role/role")
+			r := &request.Request{Config: aws.Config{Credentials: creds}}
+
+			handled := &sync.Map{}
+			if tt.args.handled {
+				handled.Store(creds, struct{}{})
+			}
+
+			// Act
+			injectFileCacheProvider(r, handled, func(o *FileCacheOptions) {
+				o.FileCacheDir = filepath.Join(tempDir, "cache")
+			})
+
+			// Assert
+			_, ok := handled.Load(creds)
+			assert.True(t, ok)
+
+			credsAccessor, _ := NewCredentialsUnsafeAccessor(creds)
+			if tt.expected.injected {
+				assert.IsType(t, &FileCacheProvider{}, credsAccessor.Provider())
+			} else {
+				assert.IsType(t, &stscreds.AssumeRoleProvider{}, credsAccessor.Provider())
+			}
+		})
+	}
+}
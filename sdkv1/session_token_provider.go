@@ -0,0 +1,129 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+const (
+	SessionTokenProviderName = "SessionTokenProvider"
+)
+
+type GetSessionTokenAPIClient interface {
+	GetSessionTokenWithContext(ctx aws.Context, input *sts.GetSessionTokenInput, opts ...request.Option) (*sts.GetSessionTokenOutput, error)
+}
+
+var _ GetSessionTokenAPIClient = &sts.STS{}
+
+type SessionTokenProvider struct {
+	credentials.Expiry
+	Client        GetSessionTokenAPIClient
+	SerialNumber  *string
+	TokenProvider func() (string, error)
+	Duration      time.Duration
+	ExpiryWindow  time.Duration
+}
+
+var _ interface {
+	expireProviderWithContext
+} = &SessionTokenProvider{}
+
+func NewSessionTokenProvider(c client.ConfigProvider, options ...func(p *SessionTokenProvider)) *SessionTokenProvider {
+	return NewSessionTokenProviderWithClient(sts.New(c), options...)
+}
+
+func NewSessionTokenProviderWithClient(client GetSessionTokenAPIClient, options ...func(p *SessionTokenProvider)) *SessionTokenProvider {
+	p := &SessionTokenProvider{
+		Client: client,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return p
+}
+
+func (p *SessionTokenProvider) Retrieve() (credentials.Value, error) {
+	return p.RetrieveWithContext(aws.BackgroundContext())
+}
+
+func (p *SessionTokenProvider) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	input := &sts.GetSessionTokenInput{}
+
+	if p.Duration != 0 {
+		input.DurationSeconds = aws.Int64(int64(p.Duration / time.Second))
+	}
+
+	if p.SerialNumber != nil {
+		if p.TokenProvider == nil {
+			err := fmt.Errorf("%w, serial number %s", ErrTokenProviderUnset, aws.StringValue(p.SerialNumber))
+			return credentials.Value{ProviderName: SessionTokenProviderName}, err
+		}
+
+		code, err := p.TokenProvider()
+		if err != nil {
+			return credentials.Value{ProviderName: SessionTokenProviderName}, err
+		}
+
+		input.SerialNumber = p.SerialNumber
+		input.TokenCode = aws.String(code)
+	}
+
+	output, err := p.Client.GetSessionTokenWithContext(ctx, input)
+	if err != nil {
+		err = fmt.Errorf("failed to get session token, %w", err)
+		return credentials.Value{ProviderName: SessionTokenProviderName}, err
+	}
+
+	p.SetExpiration(aws.TimeValue(output.Credentials.Expiration), p.ExpiryWindow)
+
+	creds := credentials.Value{
+		AccessKeyID:     aws.StringValue(output.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(output.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(output.Credentials.SessionToken),
+		ProviderName:    SessionTokenProviderName,
+	}
+
+	return creds, nil
+}
+
+func (p *SessionTokenProvider) injectMFATokenProvider(profile string, path string, prompter credscacheutil.MFAPrompter) {
+	serialNumber := aws.StringValue(p.SerialNumber)
+	if !credscacheutil.NeedsMFATokenProvider(serialNumber, p.TokenProvider) {
+		return
+	}
+
+	p.TokenProvider = credscacheutil.NewMFATokenProvider(prompter, credscacheutil.MFAPrompt{
+		SerialNumber: serialNumber,
+		Profile:      profile,
+		CachePath:    path,
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/processcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+)
+
+// findSharedProfile returns the profile a provider was built from. The config
+// of a session does not tell its profile, so the profile named by the options
+// or the environment is tried first and every profile is searched otherwise.
+// It returns nil when no profile matches or the shared config cannot be read,
+// and ErrAmbiguousProfile when several profiles match but would not share a
+// cache entry.
+func findSharedProfile(cfg *aws.Config, name string, match func(p *credscacheutil.SharedProfile) bool, equivalent func(a, b *credscacheutil.SharedProfile) bool) (*credscacheutil.SharedProfile, error) {
+	if p, err := credscacheutil.LoadSharedProfile(name); err == nil && match(p) {
+		return p, nil
+	}
+
+	profiles, err := credscacheutil.LoadSharedProfiles()
+	if err != nil {
+		// the session is already built, so the provider keys on its own settings
+		if cfg.LogLevel.AtLeast(aws.LogDebug) && cfg.Logger != nil {
+			cfg.Logger.Log("unable to read shared config,", err)
+		}
+
+		return nil, nil
+	}
+
+	var found *credscacheutil.SharedProfile
+	for _, p := range profiles {
+		if !match(p) {
+			continue
+		}
+
+		if found != nil && !equivalent(found, p) {
+			err := fmt.Errorf("%w, %s and %s", ErrAmbiguousProfile, found.Name, p.Name)
+			return nil, err
+		}
+
+		if found == nil {
+			found = p
+		}
+	}
+
+	return found, nil
+}
+
+func assumeRoleProfileMatcher(provider *stscreds.AssumeRoleProvider) func(p *credscacheutil.SharedProfile) bool {
+	return func(p *credscacheutil.SharedProfile) bool {
+		// the provider generates a session name on retrieval when the profile has none
+		return p.RoleARN == provider.RoleARN &&
+			(p.SourceProfileName != "" || p.CredentialSource != "") &&
+			aws.StringValue(p.ExternalID) == aws.StringValue(provider.ExternalID) &&
+			aws.StringValue(p.MFASerial) == aws.StringValue(provider.SerialNumber) &&
+			(p.RoleSessionName == "" || p.RoleSessionName == provider.RoleSessionName)
+	}
+}
+
+// equivalentAssumeRoleProfiles tells whether two matching profiles share the
+// role cache key and the source profile of the MFA session.
+func equivalentAssumeRoleProfiles(a, b *credscacheutil.SharedProfile) bool {
+	if (a.DurationSeconds == nil) != (b.DurationSeconds == nil) {
+		return false
+	}
+
+	if a.DurationSeconds != nil && *a.DurationSeconds != *b.DurationSeconds {
+		return false
+	}
+
+	return a.SourceProfileName == b.SourceProfileName
+}
+
+func processProfileMatcher(provider *processcreds.ProcessProvider) func(p *credscacheutil.SharedProfile) bool {
+	var command string
+	if accessor, err := NewProcessProviderUnsafeAccessor(provider); err == nil {
+		command = strings.Join(accessor.Command(), " ")
+	}
+
+	return func(p *credscacheutil.SharedProfile) bool {
+		return p.CredentialProcess != "" && p.CredentialProcess == command
+	}
+}
+
+// equivalentProcessProfiles reports false, since the profile name is part of
+// the credential process cache key.
+func equivalentProcessProfiles(a, b *credscacheutil.SharedProfile) bool {
+	return false
+}
@@ -21,10 +21,12 @@
 package credscache
 
 import (
+	"os/exec"
 	"reflect"
 	"unsafe"
 
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/processcreds"
 )
 
 type CredentialsUnsafeAccessor struct {
@@ -59,3 +61,47 @@ func (a *CredentialsUnsafeAccessor) SetProvider(provider credentials.Provider) {
 	ptr := a.provider()
 	*ptr = provider
 }
+
+type ProcessProviderUnsafeAccessor struct {
+	ptr *processcreds.ProcessProvider
+}
+
+func NewProcessProviderUnsafeAccessor(ptr *processcreds.ProcessProvider) (*ProcessProviderUnsafeAccessor, error) {
+	if ptr == nil {
+		return nil, ErrNilPointer
+	}
+
+	a := &ProcessProviderUnsafeAccessor{
+		ptr: ptr,
+	}
+
+	return a, nil
+}
+
+func (a *ProcessProviderUnsafeAccessor) originalCommand() *[]string {
+	v := reflect.ValueOf(a.ptr).Elem()
+	f := v.FieldByName("originalCommand")
+	ptr := (*[]string)(unsafe.Pointer(f.UnsafeAddr()))
+	return ptr
+}
+
+func (a *ProcessProviderUnsafeAccessor) command() **exec.Cmd {
+	v := reflect.ValueOf(a.ptr).Elem()
+	f := v.FieldByName("command")
+	ptr := (**exec.Cmd)(unsafe.Pointer(f.UnsafeAddr()))
+	return ptr
+}
+
+func (a *ProcessProviderUnsafeAccessor) Command() []string {
+	// the provider replaces the command with a shell invocation on the first
+	// retrieval and keeps the original arguments aside
+	if ptr := a.originalCommand(); len(*ptr) > 0 {
+		return *ptr
+	}
+
+	if ptr := a.command(); *ptr != nil {
+		return (*ptr).Args
+	}
+
+	return nil
+}
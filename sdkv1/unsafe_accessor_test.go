@@ -24,6 +24,7 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/processcreds"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/stretchr/testify/assert"
 )
@@ -151,3 +152,90 @@ func TestCredentialsUnsafeAccessor_SetProvider(t *testing.T) {
 		})
 	}
 }
+
+func TestNewProcessProviderUnsafeAccessor(t *testing.T) {
+	type args struct {
+		ptr *processcreds.ProcessProvider
+	}
+
+	type expected struct {
+		res *ProcessProviderUnsafeAccessor
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: ProcessProvider",
+			args: args{
+				ptr: &processcreds.ProcessProvider{},
+			},
+			expected: expected{
+				res: &ProcessProviderUnsafeAccessor{ptr: &processcreds.ProcessProvider{}},
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: nil ProcessProvider",
+			args: args{
+				ptr: nil,
+			},
+			expected: expected{
+				res: nil,
+				err: ErrNilPointer,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewProcessProviderUnsafeAccessor(tt.args.ptr)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
+
+func TestProcessProviderUnsafeAccessor_Command(t *testing.T) {
+	type expected struct {
+		res []string
+	}
+
+	tests := []struct {
+		name     string
+		accessor *ProcessProviderUnsafeAccessor
+		expected expected
+	}{
+		{
+			name:     "positive case: get command",
+			accessor: &ProcessProviderUnsafeAccessor{ptr: newProcessProvider("/usr/local/bin/credential-process")},
+			expected: expected{
+				res: []string{"/usr/local/bin/credential-process"},
+			},
+		},
+		{
+			name:     "positive case: get nil command",
+			accessor: &ProcessProviderUnsafeAccessor{ptr: &processcreds.ProcessProvider{}},
+			expected: expected{
+				res: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.accessor.Command()
+
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
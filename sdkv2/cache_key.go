@@ -21,23 +21,212 @@
 package credscache
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
-func AssumeRoleCacheKey(provider *stscreds.AssumeRoleProvider) (string, error) {
-	accessor, err := NewAssumeRoleProviderUnsafeAccessor(provider)
+type AssumeRoleCacheKeyOptions struct {
+	SharedProfile   *credscacheutil.SharedProfile
+	STSEndpoint     bool
+	SourceAccessKey bool
+}
+
+type SessionTokenCacheKeyOptions struct {
+	SourceAccessKey bool
+}
+
+func AssumeRoleCacheKey(provider *stscreds.AssumeRoleProvider, optFns ...func(o *AssumeRoleCacheKeyOptions)) (string, error) {
+	o := AssumeRoleCacheKeyOptions{}
+
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	if o.SourceAccessKey {
+		k, err := assumeRoleSourceAccessKeyCacheKey(provider, optFns...)
+		if err != nil {
+			return "", err
+		}
+
+		return k.resolve(context.Background())
+	}
+
+	g, _, err := assumeRoleCacheKeyGenerator(provider, o)
 	if err != nil {
 		return "", err
 	}
 
+	return g.CacheKey()
+}
+
+// assumeRoleSourceAccessKeyCacheKey defers the source access key hash of an
+// assume role cache key to the first retrieval.
+func assumeRoleSourceAccessKeyCacheKey(provider *stscreds.AssumeRoleProvider, optFns ...func(o *AssumeRoleCacheKeyOptions)) (*sourceAccessKeyCacheKey, error) {
+	o := AssumeRoleCacheKeyOptions{}
+
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	g, client, err := assumeRoleCacheKeyGenerator(provider, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSourceAccessKeyCacheKey(client, func(hash string) (string, error) {
+		g.SourceAccessKeyHash = hash
+		return g.CacheKey()
+	})
+}
+
+func assumeRoleCacheKeyGenerator(provider *stscreds.AssumeRoleProvider, o AssumeRoleCacheKeyOptions) (*credscacheutil.AssumeRoleCacheKeyGenerator, stscreds.AssumeRoleAPIClient, error) {
+	accessor, err := NewAssumeRoleProviderUnsafeAccessor(provider)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	options := accessor.Options()
+	duration := options.Duration
+	if p := o.SharedProfile; p != nil && p.RoleARN == options.RoleARN {
+		// the profile tells whether duration_seconds was set, which the config
+		// loader drops when it is 15 minutes or less
+		duration = 0
+		if p.DurationSeconds != nil {
+			duration = *p.DurationSeconds
+		}
+	}
+
 	g := &credscacheutil.AssumeRoleCacheKeyGenerator{
 		RoleARN:         options.RoleARN,
 		RoleSessionName: options.RoleSessionName,
 		ExternalID:      options.ExternalID,
 		SerialNumber:    options.SerialNumber,
-		Duration:        options.Duration,
+		Duration:        duration,
+	}
+
+	if o.STSEndpoint {
+		endpoint, err := stsEndpoint(options.Client)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		g.Partition = endpoint.PartitionID
+		g.STSEndpoint = endpoint.URL
+	}
+
+	return g, options.Client, nil
+}
+
+func stsClientOptions(c interface{}) (sts.Options, error) {
+	client, ok := c.(*sts.Client)
+	if !ok {
+		err := fmt.Errorf("%w, sts client %T", ErrUnsupportedClient, c)
+		return sts.Options{}, err
+	}
+
+	accessor, err := NewSTSClientUnsafeAccessor(client)
+	if err != nil {
+		return sts.Options{}, err
+	}
+
+	return accessor.Options(), nil
+}
+
+// stsEndpoint resolves the endpoint of an STS client the same way as the
+// client does, so the partition follows the region of the client.
+func stsEndpoint(c interface{}) (aws.Endpoint, error) {
+	options, err := stsClientOptions(c)
+	if err != nil {
+		return aws.Endpoint{}, err
+	}
+
+	resolver := options.EndpointResolver
+	if resolver == nil {
+		resolver = sts.NewDefaultEndpointResolver()
+	}
+
+	endpoint, err := resolver.ResolveEndpoint(options.Region, options.EndpointOptions)
+	if err != nil {
+		err = fmt.Errorf("failed to resolve sts endpoint, %w", err)
+		return aws.Endpoint{}, err
+	}
+
+	return endpoint, nil
+}
+
+func SessionTokenCacheKey(provider *SessionTokenProvider, profile string, optFns ...func(o *SessionTokenCacheKeyOptions)) (string, error) {
+	if provider == nil {
+		return "", ErrNilPointer
+	}
+
+	o := SessionTokenCacheKeyOptions{}
+
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	if o.SourceAccessKey {
+		k, err := sessionTokenSourceAccessKeyCacheKey(provider, profile)
+		if err != nil {
+			return "", err
+		}
+
+		return k.resolve(context.Background())
+	}
+
+	return sessionTokenCacheKeyGenerator(provider, profile).CacheKey()
+}
+
+// sessionTokenSourceAccessKeyCacheKey defers the source access key hash of a
+// session token cache key to the first retrieval.
+func sessionTokenSourceAccessKeyCacheKey(provider *SessionTokenProvider, profile string) (*sourceAccessKeyCacheKey, error) {
+	if provider == nil {
+		return nil, ErrNilPointer
+	}
+
+	g := sessionTokenCacheKeyGenerator(provider, profile)
+
+	return newSourceAccessKeyCacheKey(provider.options.Client, func(hash string) (string, error) {
+		g.SourceAccessKeyHash = hash
+		return g.CacheKey()
+	})
+}
+
+func sessionTokenCacheKeyGenerator(provider *SessionTokenProvider, profile string) *credscacheutil.SessionTokenCacheKeyGenerator {
+	return &credscacheutil.SessionTokenCacheKeyGenerator{
+		SerialNumber: provider.options.SerialNumber,
+		Duration:     provider.options.Duration,
+		Profile:      profile,
+	}
+}
+
+func ProcessCacheKey(provider *processcreds.Provider, profile string) (string, error) {
+	accessor, err := NewProcessProviderUnsafeAccessor(provider)
+	if err != nil {
+		return "", err
+	}
+
+	var args []string
+	switch builder := accessor.CommandBuilder().(type) {
+	case processcreds.DefaultNewCommandBuilder:
+		args = builder.Args
+	case *processcreds.DefaultNewCommandBuilder:
+		args = builder.Args
+	default:
+		err := fmt.Errorf("%w, command builder %T", ErrUnsupportedCommand, builder)
+		return "", err
+	}
+
+	g := &credscacheutil.ProcessCacheKeyGenerator{
+		Command: strings.Join(args, " "),
+		Profile: profile,
 	}
 
 	return g.CacheKey()
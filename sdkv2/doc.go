@@ -23,8 +23,10 @@
 //
 // # Inject the file cache provider
 //
-// By default, the file cache provider outputs cache files to the current
-// directory.
+// By default, the file cache provider outputs cache files to
+// `$HOME/.aws/cli/cache` and shares cache with the AWS CLI (experimental
+// feature). The directory next to `AWS_CONFIG_FILE` is used if it is set, and
+// `AWS_CREDSCACHE_FILE_CACHE_DIR` overrides both.
 //
 //	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithAssumeRoleCredentialOptions(func(options *stscreds.AssumeRoleOptions) {
 //		options.TokenProvider = stscreds.StdinTokenProvider
@@ -33,17 +35,21 @@
 //		log.Fatal(err)
 //	}
 //
-//	injected, err := credscache.InjectFileCacheProvider(&cfg)
+//	result, err := credscache.InjectFileCacheProvider(&cfg)
 //	if err != nil {
 //		log.Fatal(err)
 //	}
 //
-//	if !injected {
-//		log.Print("unable to inject file cache provider")
+//	if !result.Injected {
+//		log.Print(result)
 //	}
 //
-// You can share cache with the AWS CLI by specifying `$HOME/.aws/cli/cache`
-// (experimental feature).
+// A bare provider assigned to cfg.Credentials is wrapped in a new
+// aws.CredentialsCache. The returned InjectionResult describes the provider
+// chain, what was wrapped, the cache key and file path, or the SkipReason
+// when nothing is injected.
+//
+// You can also specify the cache directory.
 //
 //	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithAssumeRoleCredentialOptions(func(options *stscreds.AssumeRoleOptions) {
 //		options.TokenProvider = stscreds.StdinTokenProvider
@@ -52,15 +58,268 @@
 //		log.Fatal(err)
 //	}
 //
-//	injected, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+//	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
 //		home, _ := os.UserHomeDir()
-//		o.FileCacheDir = filepath.Join(home, ".aws/cli/cache")
+//		o.FileCacheDir = filepath.Join(home, ".cache/aws/credscache")
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	if !result.Injected {
+//		log.Print(result)
+//	}
+//
+// # Read through multiple cache directories
+//
+// Tiers replaces FileCacheDir with an ordered list of cache directories. The
+// freshest unexpired entry among the readable tiers is used, and refreshed
+// credentials are written to every writable tier. An empty Dir is the default
+// cache directory.
+//
+//	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+//		o.Tiers = []credscacheutil.CacheTier{
+//			{Dir: "/var/cache/aws/team", Policy: credscacheutil.CacheTierPolicyReadWrite},
+//			{Dir: "", Policy: credscacheutil.CacheTierPolicyReadOnly},
+//		}
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Collect expired cache files
+//
+// Set GC to remove cache files that expired more than GCGracePeriod ago after
+// each successful store. A marker file limits collection to once per
+// GCInterval across processes.
+//
+//	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+//		o.GC = true
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Require a minimum remaining lifetime
+//
+// WithMinLifetime overrides ExpiryWindow for a single call, so that cached
+// credentials which will not last long enough are refreshed. The in-memory
+// aws.CredentialsCache in front of the file cache provider answers first, so
+// invalidate it before a call that needs a longer lifetime.
+//
+//	credsCache := cfg.Credentials.(*aws.CredentialsCache)
+//	credsCache.Invalidate()
+//
+//	creds, err := credsCache.Retrieve(credscache.WithMinLifetime(context.Background(), 45*time.Minute))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// For API calls, WithInvalidateOnMinLifetime adds a middleware that
+// invalidates it only when the credentials in memory expire within the
+// minimum lifetime of the request context.
+//
+//	cfg.APIOptions = append(cfg.APIOptions, credscache.WithInvalidateOnMinLifetime(cfg.Credentials))
+//
+//	if _, err := sts.NewFromConfig(cfg).GetCallerIdentity(credscache.WithMinLifetime(context.Background(), 45*time.Minute), &sts.GetCallerIdentityInput{}); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Invalidate and refresh cached credentials
+//
+// LookupFileCacheProvider returns the injected file cache provider. Invalidate
+// removes its cache file from every tier, and Refresh fetches and stores new
+// credentials immediately. Invalidate the aws.CredentialsCache as well so
+// that the next call reaches the file cache provider.
+//
+//	if provider, ok := credscache.LookupFileCacheProvider(&cfg); ok {
+//		if err := provider.Invalidate(context.Background()); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//
+//	if credsCache, ok := cfg.Credentials.(*aws.CredentialsCache); ok {
+//		credsCache.Invalidate()
+//	}
+//
+// # Retry on revoked credentials
+//
+// Cached credentials can be revoked before they expire. The middleware added by
+// WithInvalidateOnExpiredToken recognizes ExpiredToken, InvalidClientTokenId
+// and RequestExpired errors, invalidates the file cache provider and the
+// aws.CredentialsCache, and retries the request once with fresh credentials.
+//
+//	if _, err := credscache.InjectFileCacheProvider(&cfg); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	cfg.APIOptions = append(cfg.APIOptions, credscache.WithInvalidateOnExpiredToken(cfg.Credentials))
+//
+// # Prompt for MFA codes
+//
+// The injectors wire a prompt into an assume role provider that has an MFA
+// serial number but no token provider. The prompt shows the role ARN, profile
+// and cache path, and only runs when the cache misses. LoadDefaultConfig
+// requires a token provider for profiles with mfa_serial, so pass
+// credscacheutil.DeferredMFATokenProvider to let the injector replace it.
+// MFAPrompter selects where the code is read from.
+//
+//	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithAssumeRoleCredentialOptions(func(options *stscreds.AssumeRoleOptions) {
+//		options.TokenProvider = credscacheutil.DeferredMFATokenProvider
+//	}))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+//		o.MFAPrompter = credscacheutil.TTYMFAPrompter
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Cache MFA sessions for IAM users
+//
+// The SDK ignores mfa_serial in a profile without role_arn.
+// SessionTokenProvider calls GetSessionToken with the MFA code, and the
+// injectors cache its credentials under a key derived from the profile, the
+// serial number and the duration. The cached session can be the source
+// credentials of later AssumeRole calls.
+//
+//	profile, err := credscacheutil.LoadSharedProfile("")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	cfg.Credentials = aws.NewCredentialsCache(credscache.NewSessionTokenProvider(sts.NewFromConfig(cfg), func(o *credscache.SessionTokenOptions) {
+//		o.SerialNumber = profile.MFASerial
+//	}))
+//
+//	if _, err := credscache.InjectFileCacheProvider(&cfg); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Share an MFA session between roles
+//
+// With MFASession, the injectors obtain a GetSessionToken session for the
+// source profile of an assume role provider with an MFA serial number, cache
+// it, and assume the role with it. Every role with the same source profile
+// reuses the session, so a single MFA code lasts for MFASessionDuration (12
+// hours by default). The source profile must have IAM user credentials, and
+// the role cache key still includes the serial number for AWS CLI
+// compatibility.
+//
+//	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+//		o.MFASession = true
+//		o.MFASessionDuration = 8 * time.Hour
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Fall back to shorter role sessions
+//
+// AssumeRole fails when duration_seconds exceeds the MaxSessionDuration of the
+// role. With DurationFallback, the file cache provider retries with every
+// whole hour below the requested duration down to one hour. The credentials
+// are cached under the key of the requested duration, the cache entry records
+// EffectiveDurationSeconds, and later refreshes keep the accepted duration.
+// The MFA token provider is asked for a code only once per refresh.
+//
+//	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+//		o.DurationFallback = true
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Separate caches by STS endpoint
+//
+// The AWS CLI keys role sessions on the AssumeRole parameters alone, so roles
+// with the same ARN shape in different partitions share a cache file. With
+// STSEndpointCacheKey, the partition and the endpoint of the STS client are
+// mixed into the cache key. The key no longer matches the AWS CLI.
+//
+//	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+//		o.STSEndpointCacheKey = true
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Scope caches to the caller
+//
+// Users assuming the same role with the same session name share a cache file
+// when they point at a common cache directory. With SourceAccessKeyCacheKey,
+// a hash of the access key ID of the source credentials is mixed into the
+// role and MFA session cache keys, so one user never loads the session of
+// another. The source credentials are retrieved on the first retrieval with
+// the caller's context and again on every refresh, so the key follows a
+// rotated source key. Until then, the injection result has no cache key.
+//
+//	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+//		o.SourceAccessKeyCacheKey = true
 //	})
 //	if err != nil {
 //		log.Fatal(err)
 //	}
 //
-//	if !injected {
-//		log.Print("unable to inject file cache provider")
+// # Cache credential process output
+//
+// Credentials from `credential_process` are cached as well when the process
+// returns an `Expiration`. The cache key is derived from the command line and
+// the profile of the loaded shared config.
+//
+// # Wrap a custom provider
+//
+// WrapProvider caches any credentials provider under a key built from
+// caller-defined attributes, hashed in the same way as the AWS CLI.
+//
+//	keyer := credscacheutil.NewCacheKeyBuilder().
+//		AddString("Provider", "vault").
+//		AddString("Role", "deploy")
+//
+//	credsCache, err := credscache.WrapProvider(provider, keyer)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	cfg.Credentials = credsCache
+//
+// # Observe the file cache provider
+//
+// An observer receives cache hits, misses, expirations, refreshes, store
+// failures, corrupt entries and insecure entries. A store failure is reported
+// to the observer and the retrieved credentials are still returned unless
+// StrictStore is set. An insecure entry is skipped and never written to, even
+// with StrictStore.
+// Ready-made observers are available for `log/slog`, expvar counters, and
+// the smithy-go logger.
+//
+//	counters := credscacheutil.NewCounterObserver()
+//	counters.Publish("credscache")
+//
+//	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+//		o.Observer = credscacheutil.MultiObserver{
+//			credscache.NewLoggerObserver(cfg.Logger),
+//			counters,
+//		}
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+// # Log in with SSO
+//
+// SSOLogin runs the SSO-OIDC device authorization flow and writes the access
+// token to `$HOME/.aws/sso/cache` in the same form as `aws sso login`. The
+// start URL and region are read from the `sso_*` settings of the profile unless
+// they are given explicitly.
+//
+//	token, err := credscache.SSOLogin(context.Background(), cfg, func(o *credscache.SSOLoginOptions) {
+//		o.Profile = "my-sso-profile"
+//	})
+//	if err != nil {
+//		log.Fatal(err)
 //	}
 package credscache
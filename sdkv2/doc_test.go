@@ -28,6 +28,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
 	credscache "github.com/Aton-Kish/aws-credscache-go/sdkv2"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -60,6 +61,20 @@ func ExampleAssumeRoleCacheKey_withRoleSessionNameAndMFASerial() {
 	// cda918cacd9e1d1c71d510d187e90c5817e04b97
 }
 
+func ExampleAssumeRoleCacheKey_withSTSEndpoint() {
+	client := sts.New(sts.Options{Region: "us-gov-west-1"})
+	key, err := credscache.AssumeRoleCacheKey(stscreds.NewAssumeRoleProvider(client, "role_arn"), func(o *credscache.AssumeRoleCacheKeyOptions) {
+		o.STSEndpoint = true
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(key)
+	// Output:
+	// 6ada2148aec1c7d3434f4169f56ac4b97d7861ee
+}
+
 func ExampleLoadCredentials() {
 	path := "/home/gopher/.aws/cli/cache/de1969e7a880d858c9bef3ba110acf78869d4527.json"
 	creds, err := credscache.LoadCredentials(path)
@@ -101,13 +116,13 @@ func ExampleInjectFileCacheProvider() {
 		log.Fatal(err)
 	}
 
-	injected, err := credscache.InjectFileCacheProvider(&cfg)
+	result, err := credscache.InjectFileCacheProvider(&cfg)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if !injected {
-		log.Print("unable to inject file cache provider")
+	if !result.Injected {
+		log.Print(result)
 	}
 }
 
@@ -119,15 +134,152 @@ func ExampleInjectFileCacheProvider_specifiedFileCacheDir() {
 		log.Fatal(err)
 	}
 
-	injected, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+		home, _ := os.UserHomeDir()
+		o.FileCacheDir = filepath.Join(home, ".cache/aws/credscache")
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !result.Injected {
+		log.Print(result)
+	}
+}
+
+func ExampleInjectFileCacheProvider_withTiers() {
+	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithAssumeRoleCredentialOptions(func(options *stscreds.AssumeRoleOptions) {
+		options.TokenProvider = stscreds.StdinTokenProvider
+	}))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
 		home, _ := os.UserHomeDir()
-		o.FileCacheDir = filepath.Join(home, ".aws/cli/cache")
+		o.Tiers = []credscacheutil.CacheTier{
+			{Dir: filepath.Join(home, ".cache/aws/credscache"), Policy: credscacheutil.CacheTierPolicyReadWrite},
+			{Dir: "", Policy: credscacheutil.CacheTierPolicyReadOnly},
+		}
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !result.Injected {
+		log.Print(result)
+	}
+}
+
+func ExampleInjectFileCacheProvider_withMFAPrompter() {
+	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithAssumeRoleCredentialOptions(func(options *stscreds.AssumeRoleOptions) {
+		options.TokenProvider = credscacheutil.DeferredMFATokenProvider
+	}))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+		o.MFAPrompter = credscacheutil.NewCommandMFAPrompter("sh", "-c", `zenity --entry --text "$AWS_CREDSCACHE_MFA_PROMPT"`)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !result.Injected {
+		log.Print(result)
+	}
+}
+
+func ExampleInjectFileCacheProvider_withMFASession() {
+	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithAssumeRoleCredentialOptions(func(options *stscreds.AssumeRoleOptions) {
+		options.TokenProvider = credscacheutil.DeferredMFATokenProvider
+	}))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+		o.MFASession = true
+		o.MFASessionDuration = time.Duration(8) * time.Hour
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !result.Injected {
+		log.Print(result)
+	}
+}
+
+func ExampleNewSessionTokenProvider() {
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	profile, err := credscacheutil.LoadSharedProfile("")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg.Credentials = aws.NewCredentialsCache(credscache.NewSessionTokenProvider(sts.NewFromConfig(cfg), func(o *credscache.SessionTokenOptions) {
+		o.SerialNumber = profile.MFASerial
+		if profile.DurationSeconds != nil {
+			o.Duration = *profile.DurationSeconds
+		}
+	}))
+
+	if _, err := credscache.InjectFileCacheProvider(&cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	// the cached MFA session is the source credentials of the role
+	roleCfg := cfg.Copy()
+	roleCfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), "arn:aws:iam::123456789012:role/Admin"))
+
+	if _, err := credscache.InjectFileCacheProvider(&roleCfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleWithInvalidateOnExpiredToken() {
+	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithAssumeRoleCredentialOptions(func(options *stscreds.AssumeRoleOptions) {
+		options.TokenProvider = stscreds.StdinTokenProvider
+	}))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := credscache.InjectFileCacheProvider(&cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	cfg.APIOptions = append(cfg.APIOptions, credscache.WithInvalidateOnExpiredToken(cfg.Credentials))
+}
+
+func ExampleInjectFileCacheProvider_withObserver() {
+	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithAssumeRoleCredentialOptions(func(options *stscreds.AssumeRoleOptions) {
+		options.TokenProvider = stscreds.StdinTokenProvider
+	}))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	counters := credscacheutil.NewCounterObserver()
+	counters.Publish("credscache")
+
+	result, err := credscache.InjectFileCacheProvider(&cfg, func(o *credscache.FileCacheOptions) {
+		o.Observer = credscacheutil.MultiObserver{
+			credscache.NewLoggerObserver(cfg.Logger),
+			counters,
+		}
 	})
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if !injected {
-		log.Print("unable to inject file cache provider")
+	if !result.Injected {
+		log.Print(result)
 	}
 }
@@ -25,10 +25,16 @@ import (
 )
 
 var (
-	ErrNilPointer = credscache.ErrNilPointer
+	ErrNilPointer         = credscache.ErrNilPointer
+	ErrSSOConfigNotFound  = credscache.ErrSSOConfigNotFound
+	ErrTokenProviderUnset = credscache.ErrTokenProviderUnset
+	ErrUnsupportedClient  = credscache.ErrUnsupportedClient
+	ErrUnsupportedCommand = credscache.ErrUnsupportedCommand
 )
 
 type (
 	FileCacheProviderError = credscache.FileCacheProviderError
 	InjectionError         = credscache.InjectionError
+	InsecureCacheError     = credscache.InsecureCacheError
+	SSOLoginError          = credscache.SSOLoginError
 )
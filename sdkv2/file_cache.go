@@ -21,13 +21,15 @@
 package credscache
 
 import (
+	"time"
+
 	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
 	"github.com/aws/aws-sdk-go-v2/aws"
 )
 
-func LoadCredentials(path string) (*aws.Credentials, error) {
+func LoadCredentials(path string, optFns ...func(o *credscacheutil.FileOptions)) (*aws.Credentials, error) {
 	cache := new(credscacheutil.FileCache)
-	if err := cache.Load(path); err != nil {
+	if err := cache.Load(path, optFns...); err != nil {
 		return nil, err
 	}
 
@@ -42,7 +44,11 @@ func LoadCredentials(path string) (*aws.Credentials, error) {
 	return creds, nil
 }
 
-func StoreCredentials(path string, creds *aws.Credentials) error {
+func StoreCredentials(path string, creds *aws.Credentials, optFns ...func(o *credscacheutil.FileOptions)) error {
+	return storeCredentials(path, creds, 0, optFns...)
+}
+
+func storeCredentials(path string, creds *aws.Credentials, duration time.Duration, optFns ...func(o *credscacheutil.FileOptions)) error {
 	cache := &credscacheutil.FileCache{
 		Credentials: credscacheutil.CachedCredentials{
 			AccessKeyID:     creds.AccessKeyID,
@@ -50,9 +56,10 @@ func StoreCredentials(path string, creds *aws.Credentials) error {
 			SessionToken:    creds.SessionToken,
 			Expires:         creds.Expires,
 		},
+		EffectiveDurationSeconds: int64(duration / time.Second),
 	}
 
-	if err := cache.Store(path); err != nil {
+	if err := cache.Store(path, optFns...); err != nil {
 		return err
 	}
 
@@ -22,10 +22,14 @@ package credscache
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"sync"
 	"time"
 
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/Aton-Kish/aws-credscache-go/internal/credscache"
 	"github.com/Aton-Kish/aws-credscache-go/internal/xfilepath"
 	"github.com/aws/aws-sdk-go-v2/aws"
 )
@@ -35,19 +39,34 @@ const (
 )
 
 var (
-	defaultFileCacheDir = ""
 	defaultExpiryWindow = time.Duration(1) * time.Minute
 )
 
 type FileCacheProvider struct {
 	provider aws.CredentialsProvider
-	cacheKey string
 	options  FileCacheOptions
+
+	mu        sync.Mutex
+	cacheKey  string
+	sourceKey *sourceAccessKeyCacheKey
 }
 
 type FileCacheOptions struct {
-	FileCacheDir string
-	ExpiryWindow time.Duration
+	FileCacheDir                string
+	Tiers                       []credscacheutil.CacheTier
+	ExpiryWindow                time.Duration
+	Observer                    credscacheutil.Observer
+	StrictStore                 bool
+	DurationFallback            bool
+	GC                          bool
+	GCGracePeriod               time.Duration
+	GCInterval                  time.Duration
+	MFAPrompter                 credscacheutil.MFAPrompter
+	MFASession                  bool
+	MFASessionDuration          time.Duration
+	STSEndpointCacheKey         bool
+	SourceAccessKeyCacheKey     bool
+	InsecureSkipPermissionCheck bool
 }
 
 var _ interface {
@@ -56,7 +75,6 @@ var _ interface {
 
 func NewFileCacheProvider(provider aws.CredentialsProvider, cacheKey string, optFns ...func(o *FileCacheOptions)) *FileCacheProvider {
 	o := FileCacheOptions{
-		FileCacheDir: defaultFileCacheDir,
 		ExpiryWindow: defaultExpiryWindow,
 	}
 
@@ -72,22 +90,86 @@ func NewFileCacheProvider(provider aws.CredentialsProvider, cacheKey string, opt
 }
 
 func (p *FileCacheProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
-	path := filepath.Join(p.options.FileCacheDir, fmt.Sprintf("%s.json", p.cacheKey))
+	tiers, err := p.cacheTiers(ctx)
+	if err != nil {
+		err = &FileCacheProviderError{Err: err}
+		return aws.Credentials{Source: FileCacheProviderName}, err
+	}
 
-	if xfilepath.Exists(path) {
-		creds, err := LoadCredentials(path)
-		if err != nil {
+	checked, err := p.resolveCacheKey(ctx, false)
+	if err != nil {
+		err = &FileCacheProviderError{Err: err}
+		return aws.Credentials{Source: FileCacheProviderName}, err
+	}
+
+	cached, path, err := p.load(ctx, tiers, p.expiryWindow(ctx))
+	if cached != nil {
+		p.observe(ctx, credscacheutil.Event{Kind: credscacheutil.EventKindCacheHit, Path: path, Expires: cached.Expires})
+		return *cached, nil
+	}
+	if err != nil {
+		err = &FileCacheProviderError{Err: err}
+		return aws.Credentials{Source: FileCacheProviderName}, err
+	}
+
+	if !checked {
+		if _, err := p.resolveCacheKey(ctx, true); err != nil {
 			err = &FileCacheProviderError{Err: err}
 			return aws.Credentials{Source: FileCacheProviderName}, err
 		}
-		creds.Source = FileCacheProviderName
+	}
+
+	return p.refresh(ctx, tiers)
+}
+
+func (p *FileCacheProvider) Refresh(ctx context.Context) (aws.Credentials, error) {
+	tiers, err := p.cacheTiers(ctx)
+	if err != nil {
+		err = &FileCacheProviderError{Err: err}
+		return aws.Credentials{Source: FileCacheProviderName}, err
+	}
+
+	if _, err := p.resolveCacheKey(ctx, true); err != nil {
+		err = &FileCacheProviderError{Err: err}
+		return aws.Credentials{Source: FileCacheProviderName}, err
+	}
+
+	return p.refresh(ctx, tiers)
+}
+
+// Invalidate removes the cache file from every tier, including read-only
+// ones, since the cached credentials are known to be unusable.
+func (p *FileCacheProvider) Invalidate(ctx context.Context) error {
+	tiers, err := p.tiers()
+	if err != nil {
+		err = &FileCacheProviderError{Err: err}
+		return err
+	}
+
+	if _, err := p.resolveCacheKey(ctx, false); err != nil {
+		err = &FileCacheProviderError{Err: err}
+		return err
+	}
 
-		if creds.Expires.After(time.Now().Add(p.options.ExpiryWindow)) {
-			return *creds, nil
+	for _, tier := range tiers {
+		path := p.tierPath(tier)
+		if err := credscacheutil.RemoveFileCache(path); err != nil {
+			err = &FileCacheProviderError{Err: err}
+			return err
 		}
+
+		p.observe(ctx, credscacheutil.Event{Kind: credscacheutil.EventKindInvalidate, Path: path})
 	}
 
-	creds, err := p.provider.Retrieve(ctx)
+	return nil
+}
+
+func (p *FileCacheProvider) refresh(ctx context.Context, tiers []credscacheutil.CacheTier) (aws.Credentials, error) {
+	path := p.primaryPath(tiers)
+	p.observe(ctx, credscacheutil.Event{Kind: credscacheutil.EventKindRefreshStart, Path: path})
+	start := time.Now()
+	creds, duration, err := p.retrieve(ctx)
+	p.observe(ctx, credscacheutil.Event{Kind: credscacheutil.EventKindRefreshFinish, Path: path, Expires: creds.Expires, Latency: time.Since(start), Err: err})
 	if err != nil {
 		err = &FileCacheProviderError{Err: err}
 		return aws.Credentials{Source: FileCacheProviderName}, err
@@ -95,7 +177,7 @@ func (p *FileCacheProvider) Retrieve(ctx context.Context) (aws.Credentials, erro
 	creds.Source = FileCacheProviderName
 
 	if creds.CanExpire {
-		if err := StoreCredentials(path, &creds); err != nil {
+		if err := p.store(ctx, tiers, &creds, duration); err != nil {
 			err = &FileCacheProviderError{Err: err}
 			return aws.Credentials{Source: FileCacheProviderName}, err
 		}
@@ -103,3 +185,206 @@ func (p *FileCacheProvider) Retrieve(ctx context.Context) (aws.Credentials, erro
 
 	return creds, nil
 }
+
+// load returns the freshest unexpired credentials among the readable tiers.
+// A corrupt entry is only reported when no tier has usable credentials.
+func (p *FileCacheProvider) load(ctx context.Context, tiers []credscacheutil.CacheTier, window time.Duration) (*aws.Credentials, string, error) {
+	var fresh *aws.Credentials
+	var freshPath string
+	var corruptErr error
+	for _, tier := range tiers {
+		if !tier.Policy.CanRead() {
+			continue
+		}
+
+		path := p.tierPath(tier)
+		if !xfilepath.Exists(path) {
+			p.observe(ctx, credscacheutil.Event{Kind: credscacheutil.EventKindCacheMiss, Path: path})
+			continue
+		}
+
+		creds, err := LoadCredentials(path, p.fileOptions)
+		// an insecure entry is never trusted, and the store refuses its directory too
+		var insecureErr *InsecureCacheError
+		if errors.As(err, &insecureErr) {
+			p.observe(ctx, credscacheutil.Event{Kind: credscacheutil.EventKindInsecureEntry, Path: path, Err: err})
+			continue
+		}
+		if err != nil {
+			p.observe(ctx, credscacheutil.Event{Kind: credscacheutil.EventKindCorruptEntry, Path: path, Err: err})
+			if corruptErr == nil {
+				corruptErr = err
+			}
+			continue
+		}
+		creds.Source = FileCacheProviderName
+
+		if !creds.Expires.After(time.Now().Add(window)) {
+			p.observe(ctx, credscacheutil.Event{Kind: credscacheutil.EventKindCacheExpired, Path: path, Expires: creds.Expires})
+			continue
+		}
+
+		if fresh == nil || creds.Expires.After(fresh.Expires) {
+			fresh = creds
+			freshPath = path
+		}
+	}
+
+	return fresh, freshPath, corruptErr
+}
+
+// expiryWindow returns the minimum lifetime requested by the context, falling
+// back to ExpiryWindow.
+func (p *FileCacheProvider) expiryWindow(ctx context.Context) time.Duration {
+	if d, ok := credscache.MinLifetime(ctx); ok {
+		return d
+	}
+
+	return p.options.ExpiryWindow
+}
+
+func (p *FileCacheProvider) store(ctx context.Context, tiers []credscacheutil.CacheTier, creds *aws.Credentials, duration time.Duration) error {
+	for _, tier := range tiers {
+		if !tier.Policy.CanWrite() {
+			continue
+		}
+
+		path := p.tierPath(tier)
+		if err := storeCredentials(path, creds, duration, p.fileOptions); err != nil {
+			// refusing an insecure directory is deliberate, so StrictStore does not apply
+			var insecureErr *InsecureCacheError
+			if errors.As(err, &insecureErr) {
+				p.observe(ctx, credscacheutil.Event{Kind: credscacheutil.EventKindInsecureEntry, Path: path, Err: err})
+				continue
+			}
+
+			p.observe(ctx, credscacheutil.Event{Kind: credscacheutil.EventKindStoreFailure, Path: path, Expires: creds.Expires, Err: err})
+			if p.options.StrictStore {
+				return err
+			}
+			continue
+		}
+
+		p.collectGarbage(ctx, tier.Dir)
+	}
+
+	return nil
+}
+
+func (p *FileCacheProvider) collectGarbage(ctx context.Context, dir string) {
+	if !p.options.GC {
+		return
+	}
+
+	removed, err := credscacheutil.CollectFileCacheGarbage(dir, p.gcOptions)
+	if len(removed) > 0 || err != nil {
+		p.observe(ctx, credscacheutil.Event{Kind: credscacheutil.EventKindGarbageCollect, Path: dir, Err: err})
+	}
+}
+
+func (p *FileCacheProvider) tiers() ([]credscacheutil.CacheTier, error) {
+	return credscacheutil.ResolveCacheTiers(p.options.FileCacheDir, p.options.Tiers)
+}
+
+// cacheTiers returns the cache tiers for retrieval. The cache is an
+// optimization, so a cache directory that cannot be resolved, e.g. without a
+// home directory, is reported to the observer and leaves no tiers unless
+// StrictStore is set.
+func (p *FileCacheProvider) cacheTiers(ctx context.Context) ([]credscacheutil.CacheTier, error) {
+	tiers, err := p.tiers()
+	if err != nil {
+		p.observe(ctx, credscacheutil.Event{Kind: credscacheutil.EventKindStoreFailure, Err: err})
+		if p.options.StrictStore {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return tiers, nil
+}
+
+// resolveCacheKey works out a cache key that depends on the source access key
+// on first use, or again when recheck is set. It reports whether the key is
+// up to date, which a static key always is.
+func (p *FileCacheProvider) resolveCacheKey(ctx context.Context, recheck bool) (bool, error) {
+	if p.sourceKey == nil {
+		return true, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cacheKey != "" && !recheck {
+		return false, nil
+	}
+
+	key, err := p.sourceKey.resolve(ctx)
+	if err != nil {
+		return false, err
+	}
+	p.cacheKey = key
+
+	return true, nil
+}
+
+func (p *FileCacheProvider) key() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.cacheKey
+}
+
+func (p *FileCacheProvider) tierPath(tier credscacheutil.CacheTier) string {
+	return filepath.Join(tier.Dir, fmt.Sprintf("%s.json", p.key()))
+}
+
+func (p *FileCacheProvider) primaryPath(tiers []credscacheutil.CacheTier) string {
+	if tier, ok := credscacheutil.PrimaryCacheTier(tiers); ok {
+		return p.tierPath(tier)
+	}
+
+	if len(tiers) == 0 {
+		return ""
+	}
+
+	return p.tierPath(tiers[0])
+}
+
+// path returns the primary cache path, which is empty while a key that
+// depends on the source access key is unresolved.
+func (p *FileCacheProvider) path() (string, error) {
+	if p.key() == "" {
+		return "", nil
+	}
+
+	tiers, err := p.tiers()
+	if err != nil {
+		return "", err
+	}
+
+	return p.primaryPath(tiers), nil
+}
+
+func (p *FileCacheProvider) observe(ctx context.Context, event credscacheutil.Event) {
+	if p.options.Observer == nil {
+		return
+	}
+
+	event.CacheKey = p.key()
+	p.options.Observer.Observe(ctx, event)
+}
+
+func (p *FileCacheProvider) fileOptions(o *credscacheutil.FileOptions) {
+	o.InsecureSkipPermissionCheck = p.options.InsecureSkipPermissionCheck
+}
+
+func (p *FileCacheProvider) gcOptions(o *credscacheutil.FileCacheGCOptions) {
+	if p.options.GCGracePeriod > 0 {
+		o.GracePeriod = p.options.GCGracePeriod
+	}
+	if p.options.GCInterval > 0 {
+		o.Interval = p.options.GCInterval
+	}
+	o.InsecureSkipPermissionCheck = p.options.InsecureSkipPermissionCheck
+}
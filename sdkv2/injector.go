@@ -21,36 +21,204 @@
 package credscache
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/Aton-Kish/aws-credscache-go/internal/credscache"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 )
 
-func InjectFileCacheProvider(cfg *aws.Config, optFns ...func(o *FileCacheOptions)) (bool, error) {
-	credsCache, ok := cfg.Credentials.(*aws.CredentialsCache)
-	if !ok {
-		return false, nil
+type (
+	SkipReason      = credscache.SkipReason
+	InjectionResult = credscache.InjectionResult
+)
+
+const (
+	SkipReasonNone                = credscache.SkipReasonNone
+	SkipReasonNoCredentials       = credscache.SkipReasonNoCredentials
+	SkipReasonUnsupportedProvider = credscache.SkipReasonUnsupportedProvider
+	SkipReasonUnsupportedCommand  = credscache.SkipReasonUnsupportedCommand
+	SkipReasonAlreadyInjected     = credscache.SkipReasonAlreadyInjected
+)
+
+func InjectFileCacheProvider(cfg *aws.Config, optFns ...func(o *FileCacheOptions)) (*InjectionResult, error) {
+	result := &InjectionResult{}
+
+	if cfg.Credentials == nil {
+		result.Reason = SkipReasonNoCredentials
+		return result, nil
+	}
+
+	provider := cfg.Credentials
+	result.ProviderChain = append(result.ProviderChain, fmt.Sprintf("%T", provider))
+
+	var accessor *CredentialsCacheUnsafeAccessor
+	if credsCache, ok := cfg.Credentials.(*aws.CredentialsCache); ok {
+		var err error
+		accessor, err = NewCredentialsCacheUnsafeAccessor(credsCache)
+		if err != nil {
+			err = &InjectionError{Err: err}
+			return result, err
+		}
+
+		provider = accessor.Provider()
+		result.ProviderChain = append(result.ProviderChain, fmt.Sprintf("%T", provider))
 	}
 
-	accessor, err := NewCredentialsCacheUnsafeAccessor(credsCache)
+	o := FileCacheOptions{}
+
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	profile := profileFromConfigSources(cfg.ConfigSources)
+
+	var key string
+	var sourceKey *sourceAccessKeyCacheKey
+	var err error
+	switch provider := provider.(type) {
+	case *FileCacheProvider:
+		result.Reason = SkipReasonAlreadyInjected
+		return result, nil
+	case *stscreds.AssumeRoleProvider:
+		keyOptions := func(ko *AssumeRoleCacheKeyOptions) {
+			ko.SharedProfile = sharedProfileFromConfigSources(cfg.ConfigSources)
+			ko.STSEndpoint = o.STSEndpointCacheKey
+		}
+		if o.SourceAccessKeyCacheKey {
+			sourceKey, err = assumeRoleSourceAccessKeyCacheKey(provider, keyOptions)
+		} else {
+			key, err = AssumeRoleCacheKey(provider, keyOptions)
+		}
+	case *SessionTokenProvider:
+		if o.SourceAccessKeyCacheKey {
+			sourceKey, err = sessionTokenSourceAccessKeyCacheKey(provider, profile)
+		} else {
+			key, err = SessionTokenCacheKey(provider, profile)
+		}
+	case *processcreds.Provider:
+		key, err = ProcessCacheKey(provider, profile)
+		if errors.Is(err, ErrUnsupportedCommand) {
+			result.Reason = SkipReasonUnsupportedCommand
+			return result, nil
+		}
+	default:
+		result.Reason = SkipReasonUnsupportedProvider
+		return result, nil
+	}
 	if err != nil {
 		err = &InjectionError{Err: err}
-		return false, err
+		return result, err
 	}
 
-	provider := accessor.Provider()
-	assumeRoleProvider, ok := provider.(*stscreds.AssumeRoleProvider)
-	if !ok {
-		return false, nil
+	fileCacheProvider := NewFileCacheProvider(provider, key, optFns...)
+	fileCacheProvider.sourceKey = sourceKey
+	// the path is informational, so the error is left to Retrieve
+	path, _ := fileCacheProvider.path()
+
+	// the MFA wiring comes before the provider is swapped, so that a failure
+	// leaves the config as it was
+	switch provider := provider.(type) {
+	case *stscreds.AssumeRoleProvider:
+		injected := false
+		if fileCacheProvider.options.MFASession {
+			injected, err = injectMFASession(provider, sourceProfileFromConfigSources(cfg.ConfigSources), fileCacheProvider.options)
+		}
+		if !injected && err == nil {
+			err = injectMFATokenProvider(provider, profile, path, fileCacheProvider.options.MFAPrompter)
+		}
+		if err != nil {
+			err = &InjectionError{Err: err}
+			return result, err
+		}
+	case *SessionTokenProvider:
+		provider.injectMFATokenProvider(profile, path, fileCacheProvider.options.MFAPrompter)
+	}
+
+	if accessor != nil {
+		accessor.SetProvider(fileCacheProvider)
+	} else {
+		// a bare provider is wrapped so that credentials are also cached in memory
+		cfg.Credentials = aws.NewCredentialsCache(fileCacheProvider)
 	}
 
-	key, err := AssumeRoleCacheKey(assumeRoleProvider)
+	result.Injected = true
+	result.Wrapped = fmt.Sprintf("%T", provider)
+	result.CacheKey = key
+	result.Path = path
+
+	return result, nil
+}
+
+// injectMFATokenProvider wires a prompt into an assume role provider with an
+// MFA serial number but no token provider. The prompt only runs when the file
+// cache misses and the provider actually calls AssumeRole.
+func injectMFATokenProvider(provider *stscreds.AssumeRoleProvider, profile string, path string, prompter credscacheutil.MFAPrompter) error {
+	accessor, err := NewAssumeRoleProviderUnsafeAccessor(provider)
 	if err != nil {
-		err = &InjectionError{Err: err}
-		return false, err
+		return err
+	}
+
+	options := accessor.Options()
+	serialNumber := aws.ToString(options.SerialNumber)
+	if !credscacheutil.NeedsMFATokenProvider(serialNumber, options.TokenProvider) {
+		return nil
+	}
+
+	accessor.SetTokenProvider(credscacheutil.NewMFATokenProvider(prompter, credscacheutil.MFAPrompt{
+		RoleARN:      options.RoleARN,
+		SerialNumber: serialNumber,
+		Profile:      profile,
+		CachePath:    path,
+	}))
+
+	return nil
+}
+
+func profileFromConfigSources(configSources []interface{}) string {
+	for _, source := range configSources {
+		if sharedConfig, ok := source.(config.SharedConfig); ok && sharedConfig.Profile != "" {
+			return sharedConfig.Profile
+		}
 	}
 
-	fileCacheProvider := NewFileCacheProvider(assumeRoleProvider, key, optFns...)
-	accessor.SetProvider(fileCacheProvider)
+	return credscacheutil.ResolveProfileName("")
+}
+
+// sharedProfileFromConfigSources keeps duration_seconds as written in the
+// profile, which the config loader drops when it is 15 minutes or less.
+func sharedProfileFromConfigSources(configSources []interface{}) *credscacheutil.SharedProfile {
+	for _, source := range configSources {
+		if sharedConfig, ok := source.(config.SharedConfig); ok && sharedConfig.Profile != "" {
+			return &credscacheutil.SharedProfile{
+				Name:            sharedConfig.Profile,
+				RoleARN:         sharedConfig.RoleARN,
+				DurationSeconds: sharedConfig.RoleDurationSeconds,
+			}
+		}
+	}
+
+	return nil
+}
+
+func LookupFileCacheProvider(cfg *aws.Config) (*FileCacheProvider, bool) {
+	return lookupFileCacheProvider(cfg.Credentials)
+}
+
+func lookupFileCacheProvider(provider aws.CredentialsProvider) (*FileCacheProvider, bool) {
+	if credsCache, ok := provider.(*aws.CredentialsCache); ok {
+		accessor, err := NewCredentialsCacheUnsafeAccessor(credsCache)
+		if err != nil {
+			return nil, false
+		}
+
+		provider = accessor.Provider()
+	}
 
-	return true, nil
+	fileCacheProvider, ok := provider.(*FileCacheProvider)
+	return fileCacheProvider, ok
 }
@@ -0,0 +1,107 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// assumeRoleClientWithCredentials signs AssumeRole with the MFA session
+// instead of the credentials the client was created with.
+type assumeRoleClientWithCredentials struct {
+	stscreds.AssumeRoleAPIClient
+	credentials aws.CredentialsProvider
+}
+
+func (c *assumeRoleClientWithCredentials) AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	optFns = append(optFns, func(o *sts.Options) {
+		o.Credentials = c.credentials
+	})
+
+	return c.AssumeRoleAPIClient.AssumeRole(ctx, params, optFns...)
+}
+
+// injectMFASession makes an assume role provider with an MFA serial number
+// assume the role with a cached GetSessionToken session of the source profile,
+// so that roles sharing the source profile need a single MFA code. It returns
+// false when the client cannot call GetSessionToken.
+func injectMFASession(provider *stscreds.AssumeRoleProvider, profile string, o FileCacheOptions) (bool, error) {
+	accessor, err := NewAssumeRoleProviderUnsafeAccessor(provider)
+	if err != nil {
+		return false, err
+	}
+
+	options := accessor.options()
+	client, ok := options.Client.(GetSessionTokenAPIClient)
+	if !ok || options.SerialNumber == nil {
+		return false, nil
+	}
+
+	sessionProvider := NewSessionTokenProvider(client, func(so *SessionTokenOptions) {
+		so.SerialNumber = options.SerialNumber
+		so.TokenProvider = options.TokenProvider
+		so.Duration = o.MFASessionDuration
+	})
+
+	var key string
+	var sourceKey *sourceAccessKeyCacheKey
+	if o.SourceAccessKeyCacheKey {
+		sourceKey, err = sessionTokenSourceAccessKeyCacheKey(sessionProvider, profile)
+	} else {
+		key, err = SessionTokenCacheKey(sessionProvider, profile)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	fileCacheProvider := NewFileCacheProvider(sessionProvider, key, func(fo *FileCacheOptions) {
+		*fo = o
+	})
+	fileCacheProvider.sourceKey = sourceKey
+
+	// the path is informational, so the error is left to Retrieve
+	path, _ := fileCacheProvider.path()
+	sessionProvider.injectMFATokenProvider(profile, path, o.MFAPrompter)
+
+	options.Client = &assumeRoleClientWithCredentials{
+		AssumeRoleAPIClient: options.Client,
+		credentials:         aws.NewCredentialsCache(fileCacheProvider),
+	}
+	options.SerialNumber = nil
+	options.TokenProvider = nil
+
+	return true, nil
+}
+
+func sourceProfileFromConfigSources(configSources []interface{}) string {
+	for _, source := range configSources {
+		if sharedConfig, ok := source.(config.SharedConfig); ok && sharedConfig.SourceProfileName != "" {
+			return sharedConfig.SourceProfileName
+		}
+	}
+
+	return profileFromConfigSources(configSources)
+}
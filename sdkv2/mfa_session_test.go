@@ -0,0 +1,175 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSTSServer struct {
+	mu                   sync.Mutex
+	assumeRoleAccessKeys []string
+	sessionTokenCalls    int
+}
+
+func (s *fakeSTSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r.ParseForm()
+	w.Header().Set("Content-Type", "text/xml")
+
+	switch r.Form.Get("Action") {
+	case "AssumeRole":
+		_, credential, _ := strings.Cut(r.Header.Get("Authorization"), "Credential=")
+		accessKey, _, _ := strings.Cut(credential, "/")
+		s.assumeRoleAccessKeys = append(s.assumeRoleAccessKeys, accessKey)
+		expiration := time.Now().UTC().Add(time.Duration(1) * time.Hour).Format(time.RFC3339)
+		fmt.Fprintf(w, `<AssumeRoleResponse><AssumeRoleResult><Credentials><AccessKeyId>AssumedAccessKeyID</AccessKeyId><SecretAccessKey>AssumedSecretAccessKey</SecretAccessKey><SessionToken>AssumedSessionToken</SessionToken><Expiration>%s</Expiration></Credentials><AssumedRoleUser><Arn>arn:aws:sts::123456789012:assumed-role/role/session</Arn><AssumedRoleId>AssumedRoleID:session</AssumedRoleId></AssumedRoleUser></AssumeRoleResult><ResponseMetadata><RequestId>RequestID</RequestId></ResponseMetadata></AssumeRoleResponse>`, expiration)
+	case "GetSessionToken":
+		s.sessionTokenCalls++
+		expiration := time.Now().UTC().Add(time.Duration(12) * time.Hour).Format(time.RFC3339)
+		fmt.Fprintf(w, `<GetSessionTokenResponse><GetSessionTokenResult><Credentials><AccessKeyId>SessionAccessKeyID</AccessKeyId><SecretAccessKey>SessionSecretAccessKey</SecretAccessKey><SessionToken>SessionSessionToken</SessionToken><Expiration>%s</Expiration></Credentials></GetSessionTokenResult><ResponseMetadata><RequestId>RequestID</RequestId></ResponseMetadata></GetSessionTokenResponse>`, expiration)
+	default:
+		fmt.Fprint(w, `<GetCallerIdentityResponse><GetCallerIdentityResult><Arn>arn:aws:iam::123456789012:user/gopher</Arn><UserId>UserID</UserId><Account>123456789012</Account></GetCallerIdentityResult><ResponseMetadata><RequestId>RequestID</RequestId></ResponseMetadata></GetCallerIdentityResponse>`)
+	}
+}
+
+func TestInjectFileCacheProvider_MFASession(t *testing.T) {
+	type args struct {
+		mfaSession bool
+	}
+
+	type expected struct {
+		prompts              []*credscacheutil.MFAPrompt
+		sessionTokenCalls    int
+		assumeRoleAccessKeys []string
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: roles share MFA session",
+			args: args{
+				mfaSession: true,
+			},
+			expected: expected{
+				prompts: []*credscacheutil.MFAPrompt{
+					{SerialNumber: "arn:aws:iam::123456789012:mfa/user", Profile: "default"},
+				},
+				sessionTokenCalls:    1,
+				assumeRoleAccessKeys: []string{"SessionAccessKeyID", "SessionAccessKeyID"},
+			},
+		},
+		{
+			name: "positive case: roles prompt separately without MFA session",
+			args: args{
+				mfaSession: false,
+			},
+			expected: expected{
+				prompts: []*credscacheutil.MFAPrompt{
+					{RoleARN: "arn:aws:iam::123456789012:role/role-a", SerialNumber: "arn:aws:iam::123456789012:mfa/user", Profile: "role-a"},
+					{RoleARN: "arn:aws:iam::123456789012:role/role-b", SerialNumber: "arn:aws:iam::123456789012:mfa/user", Profile: "role-b"},
+				},
+				sessionTokenCalls:    0,
+				assumeRoleAccessKeys: []string{"AccessKeyID", "AccessKeyID"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tempDir := t.TempDir()
+			cacheDir := filepath.Join(tempDir, "cache")
+			t.Setenv("AWS_CONFIG_FILE", filepath.Join(tempDir, "config"))
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(tempDir, "credentials"))
+			t.Setenv("AWS_ACCESS_KEY_ID", "")
+			t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+			t.Setenv("AWS_PROFILE", "")
+			os.WriteFile(filepath.Join(tempDir, "config"), []byte("[default]\nregion = us-east-1\n\n"+
+				"[profile role-a]\nregion = us-east-1\nrole_arn = arn:aws:iam::123456789012:role/role-a\nsource_profile = default\nmfa_serial = arn:aws:iam::123456789012:mfa/user\n\n"+
+				"[profile role-b]\nregion = us-east-1\nrole_arn = arn:aws:iam::123456789012:role/role-b\nsource_profile = default\nmfa_serial = arn:aws:iam::123456789012:mfa/user\n"), 0600)
+			os.WriteFile(filepath.Join(tempDir, "credentials"), []byte("[default]\naws_access_key_id = AccessKeyID\naws_secret_access_key = SecretAccessKey\n"), 0600)
+
+			server := &fakeSTSServer{}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			var prompts []*credscacheutil.MFAPrompt
+			prompter := func(prompt *credscacheutil.MFAPrompt) (string, error) {
+				prompts = append(prompts, prompt)
+				return "123456", nil
+			}
+
+			// Act
+			for _, profile := range []string{"role-a", "role-b"} {
+				cfg, err := config.LoadDefaultConfig(context.Background(),
+					config.WithSharedConfigProfile(profile),
+					config.WithEndpointResolverWithOptions(aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+						return aws.Endpoint{URL: ts.URL}, nil
+					})),
+					config.WithAssumeRoleCredentialOptions(func(o *stscreds.AssumeRoleOptions) {
+						o.TokenProvider = credscacheutil.DeferredMFATokenProvider
+					}),
+				)
+				assert.NoError(t, err)
+
+				_, err = InjectFileCacheProvider(&cfg, func(o *FileCacheOptions) {
+					o.FileCacheDir = cacheDir
+					o.MFASession = tt.args.mfaSession
+					o.MFAPrompter = prompter
+				})
+				assert.NoError(t, err)
+
+				_, err = sts.NewFromConfig(cfg).GetCallerIdentity(context.Background(), &sts.GetCallerIdentityInput{})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			for _, prompt := range prompts {
+				prompt.CachePath = ""
+			}
+			assert.Equal(t, tt.expected.prompts, prompts)
+			assert.Equal(t, tt.expected.sessionTokenCalls, server.sessionTokenCalls)
+			assert.Equal(t, tt.expected.assumeRoleAccessKeys, server.assumeRoleAccessKeys)
+		})
+	}
+}
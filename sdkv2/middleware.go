@@ -0,0 +1,165 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Aton-Kish/aws-credscache-go/internal/credscache"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+)
+
+const (
+	InvalidateOnExpiredTokenMiddlewareID = "CredscacheInvalidateOnExpiredToken"
+	InvalidateOnMinLifetimeMiddlewareID  = "CredscacheInvalidateOnMinLifetime"
+	retryMiddlewareID                    = "Retry"
+)
+
+type InvalidateOnExpiredTokenMiddleware struct {
+	Credentials aws.CredentialsProvider
+}
+
+var _ interface {
+	middleware.FinalizeMiddleware
+} = &InvalidateOnExpiredTokenMiddleware{}
+
+func (m *InvalidateOnExpiredTokenMiddleware) ID() string {
+	return InvalidateOnExpiredTokenMiddlewareID
+}
+
+func (m *InvalidateOnExpiredTokenMiddleware) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	out, metadata, err := next.HandleFinalize(ctx, in)
+	if err == nil || !isExpiredTokenError(err) {
+		return out, metadata, err
+	}
+
+	if !m.invalidate(ctx) {
+		return out, metadata, err
+	}
+
+	if rewindable, ok := in.Request.(interface{ RewindStream() error }); ok {
+		if rewindErr := rewindable.RewindStream(); rewindErr != nil {
+			return out, metadata, err
+		}
+	}
+
+	// retry only once so that revoked credentials do not loop forever
+	return next.HandleFinalize(ctx, in)
+}
+
+func (m *InvalidateOnExpiredTokenMiddleware) invalidate(ctx context.Context) bool {
+	fileCacheProvider, ok := lookupFileCacheProvider(m.Credentials)
+	if !ok {
+		return false
+	}
+
+	if err := fileCacheProvider.Invalidate(ctx); err != nil {
+		return false
+	}
+
+	if credsCache, ok := m.Credentials.(*aws.CredentialsCache); ok {
+		credsCache.Invalidate()
+	}
+
+	return true
+}
+
+func AddInvalidateOnExpiredTokenMiddleware(stack *middleware.Stack, credentials aws.CredentialsProvider) error {
+	m := &InvalidateOnExpiredTokenMiddleware{Credentials: credentials}
+
+	// placed before the retry middleware so that its attempts are not multiplied
+	if _, ok := stack.Finalize.Get(retryMiddlewareID); ok {
+		return stack.Finalize.Insert(m, retryMiddlewareID, middleware.Before)
+	}
+
+	return stack.Finalize.Add(m, middleware.Before)
+}
+
+func WithInvalidateOnExpiredToken(credentials aws.CredentialsProvider) func(stack *middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return AddInvalidateOnExpiredTokenMiddleware(stack, credentials)
+	}
+}
+
+type InvalidateOnMinLifetimeMiddleware struct {
+	Credentials aws.CredentialsProvider
+}
+
+var _ interface {
+	middleware.FinalizeMiddleware
+} = &InvalidateOnMinLifetimeMiddleware{}
+
+func (m *InvalidateOnMinLifetimeMiddleware) ID() string {
+	return InvalidateOnMinLifetimeMiddlewareID
+}
+
+func (m *InvalidateOnMinLifetimeMiddleware) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	if d, ok := credscache.MinLifetime(ctx); ok {
+		m.invalidate(ctx, d)
+	}
+
+	return next.HandleFinalize(ctx, in)
+}
+
+// invalidate drops the credentials in memory when they expire within d, so
+// that signing retrieves them through the file cache provider with the same
+// minimum lifetime.
+func (m *InvalidateOnMinLifetimeMiddleware) invalidate(ctx context.Context, d time.Duration) {
+	credsCache, ok := m.Credentials.(*aws.CredentialsCache)
+	if !ok {
+		return
+	}
+
+	creds, err := credsCache.Retrieve(ctx)
+	if err != nil || !creds.CanExpire {
+		return
+	}
+
+	if creds.Expires.Before(time.Now().Add(d)) {
+		credsCache.Invalidate()
+	}
+}
+
+func AddInvalidateOnMinLifetimeMiddleware(stack *middleware.Stack, credentials aws.CredentialsProvider) error {
+	m := &InvalidateOnMinLifetimeMiddleware{Credentials: credentials}
+
+	// placed first so that the check runs once before any attempt is signed
+	return stack.Finalize.Add(m, middleware.Before)
+}
+
+func WithInvalidateOnMinLifetime(credentials aws.CredentialsProvider) func(stack *middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return AddInvalidateOnMinLifetimeMiddleware(stack, credentials)
+	}
+}
+
+func isExpiredTokenError(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return credscache.IsExpiredTokenErrorCode(apiErr.ErrorCode())
+}
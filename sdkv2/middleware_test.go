@@ -0,0 +1,399 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mock "github.com/Aton-Kish/aws-credscache-go/internal/mock/github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvalidateOnExpiredTokenMiddleware_HandleFinalize(t *testing.T) {
+	expiresIn15Minutes := time.Now().UTC().Add(time.Duration(15) * time.Minute)
+	retrievedCreds := aws.Credentials{
+		AccessKeyID:     "RetrievedAccessKeyID",
+		SecretAccessKey: "RetrievedSecretAccessKey",
+		SessionToken:    "RetrievedSessionToken",
+		Source:          "TestProvider",
+		CanExpire:       true,
+		Expires:         expiresIn15Minutes,
+	}
+
+	errExpiredToken := &smithy.GenericAPIError{Code: "ExpiredToken", Message: "The security token included in the request is expired"}
+	errAccessDenied := &smithy.GenericAPIError{Code: "AccessDenied", Message: "Access denied"}
+	errUnknown := errors.New("unknown error")
+
+	type fields struct {
+		injected bool
+	}
+
+	type args struct {
+		errs []error
+	}
+
+	type expected struct {
+		calls         int
+		retrieveTimes int
+		invalidated   bool
+		err           error
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: succeeded",
+			fields: fields{
+				injected: true,
+			},
+			args: args{
+				errs: []error{nil},
+			},
+			expected: expected{
+				calls:         1,
+				retrieveTimes: 0,
+				invalidated:   false,
+				err:           nil,
+			},
+		},
+		{
+			name: "positive case: retried with fresh credentials",
+			fields: fields{
+				injected: true,
+			},
+			args: args{
+				errs: []error{errExpiredToken, nil},
+			},
+			expected: expected{
+				calls:         2,
+				retrieveTimes: 1,
+				invalidated:   true,
+				err:           nil,
+			},
+		},
+		{
+			name: "negative case: retried only once",
+			fields: fields{
+				injected: true,
+			},
+			args: args{
+				errs: []error{errExpiredToken, errExpiredToken},
+			},
+			expected: expected{
+				calls:         2,
+				retrieveTimes: 1,
+				invalidated:   true,
+				err:           errExpiredToken,
+			},
+		},
+		{
+			name: "negative case: other api error",
+			fields: fields{
+				injected: true,
+			},
+			args: args{
+				errs: []error{errAccessDenied},
+			},
+			expected: expected{
+				calls:         1,
+				retrieveTimes: 0,
+				invalidated:   false,
+				err:           errAccessDenied,
+			},
+		},
+		{
+			name: "negative case: other error",
+			fields: fields{
+				injected: true,
+			},
+			args: args{
+				errs: []error{errUnknown},
+			},
+			expected: expected{
+				calls:         1,
+				retrieveTimes: 0,
+				invalidated:   false,
+				err:           errUnknown,
+			},
+		},
+		{
+			name: "negative case: not injected",
+			fields: fields{
+				injected: false,
+			},
+			args: args{
+				errs: []error{errExpiredToken},
+			},
+			expected: expected{
+				calls:         1,
+				retrieveTimes: 1,
+				invalidated:   false,
+				err:           errExpiredToken,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cachedDir := t.TempDir()
+			path := filepath.Join(cachedDir, fmt.Sprintf("%s.json", "key"))
+			StoreCredentials(path, &aws.Credentials{AccessKeyID: "CachedAccessKeyID", CanExpire: true, Expires: expiresIn15Minutes})
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCredentialsProvider := mock.NewMockCredentialsProvider(ctrl)
+			mockCredentialsProvider.
+				EXPECT().
+				Retrieve(gomock.Any()).
+				Return(retrievedCreds, nil).
+				Times(tt.expected.retrieveTimes)
+
+			var provider aws.CredentialsProvider = mockCredentialsProvider
+			if tt.fields.injected {
+				provider = NewFileCacheProvider(mockCredentialsProvider, "key", func(o *FileCacheOptions) {
+					o.FileCacheDir = cachedDir
+				})
+			}
+			credsCache := aws.NewCredentialsCache(provider)
+
+			accessKeyIDs := []string{}
+			calls := 0
+			next := middleware.FinalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				creds, _ := credsCache.Retrieve(ctx)
+				accessKeyIDs = append(accessKeyIDs, creds.AccessKeyID)
+				err := tt.args.errs[calls]
+				calls++
+				return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+			})
+
+			m := &InvalidateOnExpiredTokenMiddleware{Credentials: credsCache}
+
+			// Act
+			_, _, err := m.HandleFinalize(context.Background(), middleware.FinalizeInput{Request: smithyhttp.NewStackRequest()}, next)
+
+			// Assert
+			assert.Equal(t, tt.expected.calls, calls)
+			if tt.expected.invalidated {
+				assert.Equal(t, "RetrievedAccessKeyID", accessKeyIDs[len(accessKeyIDs)-1])
+			}
+			if tt.fields.injected {
+				stored, _ := LoadCredentials(path)
+				assert.Equal(t, tt.expected.invalidated, stored.AccessKeyID == "RetrievedAccessKeyID")
+			}
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
+
+func TestAddInvalidateOnExpiredTokenMiddleware(t *testing.T) {
+	type fields struct {
+		finalize []string
+	}
+
+	type expected struct {
+		res []string
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		expected expected
+	}{
+		{
+			name: "positive case: before retry",
+			fields: fields{
+				finalize: []string{"First", "Retry", "Signing"},
+			},
+			expected: expected{
+				res: []string{"First", InvalidateOnExpiredTokenMiddlewareID, "Retry", "Signing"},
+			},
+		},
+		{
+			name: "positive case: without retry",
+			fields: fields{
+				finalize: []string{"First", "Signing"},
+			},
+			expected: expected{
+				res: []string{InvalidateOnExpiredTokenMiddlewareID, "First", "Signing"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			stack := middleware.NewStack("test", smithyhttp.NewStackRequest)
+			for _, id := range tt.fields.finalize {
+				stack.Finalize.Add(middleware.FinalizeMiddlewareFunc(id, func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+					return next.HandleFinalize(ctx, in)
+				}), middleware.After)
+			}
+
+			// Act
+			err := WithInvalidateOnExpiredToken(aws.AnonymousCredentials{})(stack)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.res, stack.Finalize.List())
+		})
+	}
+}
+
+func TestInvalidateOnMinLifetimeMiddleware_HandleFinalize(t *testing.T) {
+	expiresIn15Minutes := time.Now().UTC().Add(time.Duration(15) * time.Minute)
+	expiresIn60Minutes := time.Now().UTC().Add(time.Duration(60) * time.Minute)
+	retrievedCreds := aws.Credentials{
+		AccessKeyID:     "RetrievedAccessKeyID",
+		SecretAccessKey: "RetrievedSecretAccessKey",
+		SessionToken:    "RetrievedSessionToken",
+		Source:          "TestProvider",
+		CanExpire:       true,
+		Expires:         expiresIn60Minutes,
+	}
+
+	type args struct {
+		minLifetime time.Duration
+	}
+
+	type expected struct {
+		accessKeyID   string
+		retrieveTimes int
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: credentials outlive the minimum lifetime",
+			args: args{
+				minLifetime: time.Duration(10) * time.Minute,
+			},
+			expected: expected{
+				accessKeyID:   "CachedAccessKeyID",
+				retrieveTimes: 0,
+			},
+		},
+		{
+			name: "positive case: credentials expire within the minimum lifetime",
+			args: args{
+				minLifetime: time.Duration(30) * time.Minute,
+			},
+			expected: expected{
+				accessKeyID:   "RetrievedAccessKeyID",
+				retrieveTimes: 1,
+			},
+		},
+		{
+			name: "positive case: without minimum lifetime",
+			args: args{
+				minLifetime: 0,
+			},
+			expected: expected{
+				accessKeyID:   "CachedAccessKeyID",
+				retrieveTimes: 0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cachedDir := t.TempDir()
+			StoreCredentials(filepath.Join(cachedDir, fmt.Sprintf("%s.json", "key")), &aws.Credentials{AccessKeyID: "CachedAccessKeyID", CanExpire: true, Expires: expiresIn15Minutes})
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCredentialsProvider := mock.NewMockCredentialsProvider(ctrl)
+			mockCredentialsProvider.
+				EXPECT().
+				Retrieve(gomock.Any()).
+				Return(retrievedCreds, nil).
+				Times(tt.expected.retrieveTimes)
+
+			credsCache := aws.NewCredentialsCache(NewFileCacheProvider(mockCredentialsProvider, "key", func(o *FileCacheOptions) {
+				o.FileCacheDir = cachedDir
+			}))
+			credsCache.Retrieve(context.Background())
+
+			ctx := context.Background()
+			if tt.args.minLifetime > 0 {
+				ctx = WithMinLifetime(ctx, tt.args.minLifetime)
+			}
+
+			accessKeyID := ""
+			next := middleware.FinalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				creds, _ := credsCache.Retrieve(ctx)
+				accessKeyID = creds.AccessKeyID
+				return middleware.FinalizeOutput{}, middleware.Metadata{}, nil
+			})
+
+			m := &InvalidateOnMinLifetimeMiddleware{Credentials: credsCache}
+
+			// Act
+			_, _, err := m.HandleFinalize(ctx, middleware.FinalizeInput{Request: smithyhttp.NewStackRequest()}, next)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.accessKeyID, accessKeyID)
+		})
+	}
+}
+
+func TestAddInvalidateOnMinLifetimeMiddleware(t *testing.T) {
+	// Arrange
+	stack := middleware.NewStack("test", smithyhttp.NewStackRequest)
+	for _, id := range []string{"First", "Retry", "Signing"} {
+		stack.Finalize.Add(middleware.FinalizeMiddlewareFunc(id, func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			return next.HandleFinalize(ctx, in)
+		}), middleware.After)
+	}
+
+	// Act
+	err := WithInvalidateOnMinLifetime(aws.AnonymousCredentials{})(stack)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{InvalidateOnMinLifetimeMiddlewareID, "First", "Retry", "Signing"}, stack.Finalize.List())
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/smithy-go/logging"
+)
+
+type LoggerObserver struct {
+	logger logging.Logger
+}
+
+var _ interface {
+	credscacheutil.Observer
+} = &LoggerObserver{}
+
+func NewLoggerObserver(logger logging.Logger) *LoggerObserver {
+	if logger == nil {
+		logger = logging.Nop{}
+	}
+
+	return &LoggerObserver{
+		logger: logger,
+	}
+}
+
+func (o *LoggerObserver) Observe(ctx context.Context, event credscacheutil.Event) {
+	classification := logging.Debug
+	if event.Err != nil || event.Kind == credscacheutil.EventKindStoreFailure || event.Kind == credscacheutil.EventKindCorruptEntry {
+		classification = logging.Warn
+	}
+
+	format := []string{"credentials cache event %s, cache key %s, path %s"}
+	v := []interface{}{event.Kind, event.CacheKey, event.Path}
+
+	if !event.Expires.IsZero() {
+		format = append(format, "expires %s")
+		v = append(v, event.Expires)
+	}
+
+	if event.Kind == credscacheutil.EventKindRefreshFinish {
+		format = append(format, "latency %s")
+		v = append(v, event.Latency)
+	}
+
+	if event.Err != nil {
+		format = append(format, "error %v")
+		v = append(v, event.Err)
+	}
+
+	logging.WithContext(ctx, o.logger).Logf(classification, strings.Join(format, ", "), v...)
+}
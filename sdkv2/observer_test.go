@@ -0,0 +1,115 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/smithy-go/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerObserver_Observe(t *testing.T) {
+	type args struct {
+		event credscacheutil.Event
+	}
+
+	type expected struct {
+		classification logging.Classification
+		res            string
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: cache hit",
+			args: args{
+				event: credscacheutil.Event{
+					Kind:     credscacheutil.EventKindCacheHit,
+					CacheKey: "key",
+					Path:     "key.json",
+					Expires:  time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+				},
+			},
+			expected: expected{
+				classification: logging.Debug,
+				res:            "credentials cache event CacheHit, cache key key, path key.json, expires 2006-01-02 15:04:05 +0000 UTC",
+			},
+		},
+		{
+			name: "positive case: refresh finish",
+			args: args{
+				event: credscacheutil.Event{
+					Kind:     credscacheutil.EventKindRefreshFinish,
+					CacheKey: "key",
+					Path:     "key.json",
+					Latency:  time.Duration(3) * time.Second,
+				},
+			},
+			expected: expected{
+				classification: logging.Debug,
+				res:            "credentials cache event RefreshFinish, cache key key, path key.json, latency 3s",
+			},
+		},
+		{
+			name: "positive case: store failure",
+			args: args{
+				event: credscacheutil.Event{
+					Kind:     credscacheutil.EventKindStoreFailure,
+					CacheKey: "key",
+					Path:     "key.json",
+					Err:      errors.New("read-only file system"),
+				},
+			},
+			expected: expected{
+				classification: logging.Warn,
+				res:            "credentials cache event StoreFailure, cache key key, path key.json, error read-only file system",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var classification logging.Classification
+			var actual string
+			observer := NewLoggerObserver(logging.LoggerFunc(func(c logging.Classification, format string, v ...interface{}) {
+				classification = c
+				actual = fmt.Sprintf(format, v...)
+			}))
+
+			// Act
+			observer.Observe(context.Background(), tt.args.event)
+
+			// Assert
+			assert.Equal(t, tt.expected.classification, classification)
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+const (
+	SessionTokenProviderName = "SessionTokenProvider"
+)
+
+type GetSessionTokenAPIClient interface {
+	GetSessionToken(ctx context.Context, params *sts.GetSessionTokenInput, optFns ...func(*sts.Options)) (*sts.GetSessionTokenOutput, error)
+}
+
+var _ GetSessionTokenAPIClient = &sts.Client{}
+
+type SessionTokenProvider struct {
+	options SessionTokenOptions
+}
+
+type SessionTokenOptions struct {
+	Client        GetSessionTokenAPIClient
+	SerialNumber  *string
+	TokenProvider func() (string, error)
+	Duration      time.Duration
+}
+
+var _ interface {
+	aws.CredentialsProvider
+} = &SessionTokenProvider{}
+
+func NewSessionTokenProvider(client GetSessionTokenAPIClient, optFns ...func(o *SessionTokenOptions)) *SessionTokenProvider {
+	o := SessionTokenOptions{
+		Client: client,
+	}
+
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	return &SessionTokenProvider{
+		options: o,
+	}
+}
+
+func (p *SessionTokenProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	input := &sts.GetSessionTokenInput{}
+
+	if p.options.Duration != 0 {
+		input.DurationSeconds = aws.Int32(int32(p.options.Duration / time.Second))
+	}
+
+	if p.options.SerialNumber != nil {
+		if p.options.TokenProvider == nil {
+			err := fmt.Errorf("%w, serial number %s", ErrTokenProviderUnset, aws.ToString(p.options.SerialNumber))
+			return aws.Credentials{Source: SessionTokenProviderName}, err
+		}
+
+		code, err := p.options.TokenProvider()
+		if err != nil {
+			return aws.Credentials{Source: SessionTokenProviderName}, err
+		}
+
+		input.SerialNumber = p.options.SerialNumber
+		input.TokenCode = aws.String(code)
+	}
+
+	output, err := p.options.Client.GetSessionToken(ctx, input)
+	if err != nil {
+		err = fmt.Errorf("failed to get session token, %w", err)
+		return aws.Credentials{Source: SessionTokenProviderName}, err
+	}
+
+	creds := aws.Credentials{
+		AccessKeyID:     aws.ToString(output.Credentials.AccessKeyId),
+		SecretAccessKey: aws.ToString(output.Credentials.SecretAccessKey),
+		SessionToken:    aws.ToString(output.Credentials.SessionToken),
+		Source:          SessionTokenProviderName,
+		CanExpire:       true,
+		Expires:         aws.ToTime(output.Credentials.Expiration),
+	}
+
+	return creds, nil
+}
+
+func (p *SessionTokenProvider) injectMFATokenProvider(profile string, path string, prompter credscacheutil.MFAPrompter) {
+	serialNumber := aws.ToString(p.options.SerialNumber)
+	if !credscacheutil.NeedsMFATokenProvider(serialNumber, p.options.TokenProvider) {
+		return
+	}
+
+	p.options.TokenProvider = credscacheutil.NewMFATokenProvider(prompter, credscacheutil.MFAPrompt{
+		SerialNumber: serialNumber,
+		Profile:      profile,
+		CachePath:    path,
+	})
+}
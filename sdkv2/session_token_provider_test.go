@@ -0,0 +1,212 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeGetSessionTokenClient struct {
+	input *sts.GetSessionTokenInput
+	err   error
+}
+
+func (c *fakeGetSessionTokenClient) GetSessionToken(ctx context.Context, params *sts.GetSessionTokenInput, optFns ...func(*sts.Options)) (*sts.GetSessionTokenOutput, error) {
+	c.input = params
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	output := &sts.GetSessionTokenOutput{
+		Credentials: &types.Credentials{
+			AccessKeyId:     aws.String("AccessKeyID"),
+			SecretAccessKey: aws.String("SecretAccessKey"),
+			SessionToken:    aws.String("SessionToken"),
+			Expiration:      aws.Time(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)),
+		},
+	}
+
+	return output, nil
+}
+
+func TestSessionTokenProvider_Retrieve(t *testing.T) {
+	errClient := errors.New("failed to call")
+	errToken := errors.New("failed to read token")
+
+	type fields struct {
+		serialNumber  *string
+		tokenProvider func() (string, error)
+		duration      time.Duration
+		err           error
+	}
+
+	type expected struct {
+		res   aws.Credentials
+		input *sts.GetSessionTokenInput
+		err   error
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		expected expected
+	}{
+		{
+			name: "positive case: without MFA",
+			fields: fields{
+				serialNumber:  nil,
+				tokenProvider: nil,
+				duration:      0,
+				err:           nil,
+			},
+			expected: expected{
+				res: aws.Credentials{
+					AccessKeyID:     "AccessKeyID",
+					SecretAccessKey: "SecretAccessKey",
+					SessionToken:    "SessionToken",
+					Source:          SessionTokenProviderName,
+					CanExpire:       true,
+					Expires:         time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+				},
+				input: &sts.GetSessionTokenInput{},
+				err:   nil,
+			},
+		},
+		{
+			name: "positive case: with MFA, Duration",
+			fields: fields{
+				serialNumber:  aws.String("arn:aws:iam::123456789012:mfa/user"),
+				tokenProvider: func() (string, error) { return "123456", nil },
+				duration:      time.Duration(12) * time.Hour,
+				err:           nil,
+			},
+			expected: expected{
+				res: aws.Credentials{
+					AccessKeyID:     "AccessKeyID",
+					SecretAccessKey: "SecretAccessKey",
+					SessionToken:    "SessionToken",
+					Source:          SessionTokenProviderName,
+					CanExpire:       true,
+					Expires:         time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+				},
+				input: &sts.GetSessionTokenInput{
+					DurationSeconds: aws.Int32(43200),
+					SerialNumber:    aws.String("arn:aws:iam::123456789012:mfa/user"),
+					TokenCode:       aws.String("123456"),
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: MFA without token provider",
+			fields: fields{
+				serialNumber:  aws.String("arn:aws:iam::123456789012:mfa/user"),
+				tokenProvider: nil,
+				err:           nil,
+			},
+			expected: expected{
+				input: nil,
+				err:   ErrTokenProviderUnset,
+			},
+		},
+		{
+			name: "negative case: token provider failure",
+			fields: fields{
+				serialNumber:  aws.String("arn:aws:iam::123456789012:mfa/user"),
+				tokenProvider: func() (string, error) { return "", errToken },
+				err:           nil,
+			},
+			expected: expected{
+				input: nil,
+				err:   errToken,
+			},
+		},
+		{
+			name: "negative case: client failure",
+			fields: fields{
+				serialNumber:  nil,
+				tokenProvider: nil,
+				err:           errClient,
+			},
+			expected: expected{
+				input: &sts.GetSessionTokenInput{},
+				err:   errClient,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			client := &fakeGetSessionTokenClient{err: tt.fields.err}
+			provider := NewSessionTokenProvider(client, func(o *SessionTokenOptions) {
+				o.SerialNumber = tt.fields.serialNumber
+				o.TokenProvider = tt.fields.tokenProvider
+				o.Duration = tt.fields.duration
+			})
+
+			// Act
+			actual, err := provider.Retrieve(context.Background())
+
+			// Assert
+			assert.Equal(t, tt.expected.input, client.input)
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.ErrorIs(t, err, tt.expected.err)
+				assert.Equal(t, SessionTokenProviderName, actual.Source)
+			}
+		})
+	}
+}
+
+func TestSessionTokenProvider_injectMFATokenProvider(t *testing.T) {
+	// Arrange
+	var prompt *credscacheutil.MFAPrompt
+	provider := NewSessionTokenProvider(&fakeGetSessionTokenClient{}, func(o *SessionTokenOptions) {
+		o.SerialNumber = aws.String("arn:aws:iam::123456789012:mfa/user")
+	})
+
+	// Act
+	provider.injectMFATokenProvider("user", "/path/to/cache.json", func(p *credscacheutil.MFAPrompt) (string, error) {
+		prompt = p
+		return "123456", nil
+	})
+	_, err := provider.Retrieve(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &credscacheutil.MFAPrompt{
+		SerialNumber: "arn:aws:iam::123456789012:mfa/user",
+		Profile:      "user",
+		CachePath:    "/path/to/cache.json",
+	}, prompt)
+}
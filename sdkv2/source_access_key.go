@@ -0,0 +1,72 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// sourceAccessKeyCacheKey works out a cache key that mixes in the hash of the
+// source access key ID. The file cache provider resolves it with the context
+// of the first retrieval rather than on injection, and again on refresh since
+// temporary source credentials rotate.
+type sourceAccessKeyCacheKey struct {
+	credentials aws.CredentialsProvider
+	cacheKey    func(hash string) (string, error)
+}
+
+func newSourceAccessKeyCacheKey(c interface{}, cacheKey func(hash string) (string, error)) (*sourceAccessKeyCacheKey, error) {
+	options, err := stsClientOptions(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Credentials == nil {
+		err := fmt.Errorf("%w, source credentials", ErrNilPointer)
+		return nil, err
+	}
+
+	k := &sourceAccessKeyCacheKey{
+		credentials: options.Credentials,
+		cacheKey:    cacheKey,
+	}
+
+	return k, nil
+}
+
+func (k *sourceAccessKeyCacheKey) resolve(ctx context.Context) (string, error) {
+	creds, err := k.credentials.Retrieve(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to retrieve source credentials, %w", err)
+		return "", err
+	}
+
+	hash, err := credscacheutil.AccessKeyIDHash(creds.AccessKeyID)
+	if err != nil {
+		return "", err
+	}
+
+	return k.cacheKey(hash)
+}
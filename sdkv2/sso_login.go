@@ -0,0 +1,290 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+)
+
+const (
+	defaultSSOClientName       = "aws-credscache-go"
+	ssoClientType              = "public"
+	ssoDeviceCodeGrantType     = "urn:ietf:params:oauth:grant-type:device_code"
+	defaultSSOPollingInterval  = 5 * time.Second
+	ssoSlowDownIntervalPenalty = 5 * time.Second
+)
+
+type SSOOIDCClient interface {
+	RegisterClient(ctx context.Context, params *ssooidc.RegisterClientInput, optFns ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error)
+	StartDeviceAuthorization(ctx context.Context, params *ssooidc.StartDeviceAuthorizationInput, optFns ...func(*ssooidc.Options)) (*ssooidc.StartDeviceAuthorizationOutput, error)
+	CreateToken(ctx context.Context, params *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error)
+}
+
+var _ SSOOIDCClient = &ssooidc.Client{}
+
+type SSODeviceAuthorization struct {
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string
+	ExpiresAt               time.Time
+}
+
+type SSOLoginOptions struct {
+	Profile                     string
+	SessionName                 string
+	StartURL                    string
+	Region                      string
+	Scopes                      []string
+	ClientName                  string
+	SSOTokenCacheDir            string
+	Client                      SSOOIDCClient
+	Prompt                      func(ctx context.Context, authorization *SSODeviceAuthorization) error
+	OpenBrowser                 func(url string) error
+	NoBrowser                   bool
+	InsecureSkipPermissionCheck bool
+}
+
+var sleepWithContext = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func SSOLogin(ctx context.Context, cfg aws.Config, optFns ...func(o *SSOLoginOptions)) (*credscacheutil.SSOToken, error) {
+	o := SSOLoginOptions{
+		ClientName: defaultSSOClientName,
+		Prompt:     newWriterPrompt(os.Stderr),
+	}
+
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	token, err := ssoLogin(ctx, cfg, &o)
+	if err != nil {
+		err = &SSOLoginError{Err: err}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func ssoLogin(ctx context.Context, cfg aws.Config, o *SSOLoginOptions) (*credscacheutil.SSOToken, error) {
+	if o.StartURL == "" {
+		profile, err := credscacheutil.LoadSharedProfile(o.Profile)
+		if err != nil {
+			return nil, err
+		}
+
+		o.SessionName = profile.SSOSession
+		o.StartURL = profile.SSOStartURL
+		if o.Region == "" {
+			o.Region = profile.SSORegion
+		}
+	}
+
+	if o.StartURL == "" || o.Region == "" {
+		err := fmt.Errorf("%w, profile %s", ErrSSOConfigNotFound, credscacheutil.ResolveProfileName(o.Profile))
+		return nil, err
+	}
+
+	path, err := ssoTokenCachePath(o)
+	if err != nil {
+		return nil, err
+	}
+
+	client := o.Client
+	if client == nil {
+		client = ssooidc.NewFromConfig(cfg, func(options *ssooidc.Options) {
+			options.Region = o.Region
+		})
+	}
+
+	token := &credscacheutil.SSOToken{
+		Region:   o.Region,
+		StartURL: o.StartURL,
+	}
+
+	// reuse the client registration of the cached token if it is still valid
+	cached := new(credscacheutil.SSOToken)
+	if err := cached.Load(path, o.fileOptions); err == nil && cached.ClientID != "" && cached.ClientSecret != "" &&
+		cached.RegistrationExpiresAt != nil && cached.RegistrationExpiresAt.After(time.Now()) {
+		token.ClientID = cached.ClientID
+		token.ClientSecret = cached.ClientSecret
+		token.RegistrationExpiresAt = cached.RegistrationExpiresAt
+	} else {
+		register, err := client.RegisterClient(ctx, &ssooidc.RegisterClientInput{
+			ClientName: aws.String(o.ClientName),
+			ClientType: aws.String(ssoClientType),
+			Scopes:     o.Scopes,
+		})
+		if err != nil {
+			err = fmt.Errorf("failed to register client, %w", err)
+			return nil, err
+		}
+
+		registrationExpiresAt := time.Unix(register.ClientSecretExpiresAt, 0).UTC()
+		token.ClientID = aws.ToString(register.ClientId)
+		token.ClientSecret = aws.ToString(register.ClientSecret)
+		token.RegistrationExpiresAt = &registrationExpiresAt
+	}
+
+	authorization, err := client.StartDeviceAuthorization(ctx, &ssooidc.StartDeviceAuthorizationInput{
+		ClientId:     aws.String(token.ClientID),
+		ClientSecret: aws.String(token.ClientSecret),
+		StartUrl:     aws.String(o.StartURL),
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to start device authorization, %w", err)
+		return nil, err
+	}
+
+	deviceAuthorization := &SSODeviceAuthorization{
+		UserCode:                aws.ToString(authorization.UserCode),
+		VerificationURI:         aws.ToString(authorization.VerificationUri),
+		VerificationURIComplete: aws.ToString(authorization.VerificationUriComplete),
+		ExpiresAt:               time.Now().Add(time.Duration(authorization.ExpiresIn) * time.Second),
+	}
+
+	if o.Prompt != nil {
+		if err := o.Prompt(ctx, deviceAuthorization); err != nil {
+			err = fmt.Errorf("failed to prompt, %w", err)
+			return nil, err
+		}
+	}
+
+	if !o.NoBrowser {
+		openBrowser := o.OpenBrowser
+		if openBrowser == nil {
+			openBrowser = defaultOpenBrowser
+		}
+
+		url := deviceAuthorization.VerificationURIComplete
+		if url == "" {
+			url = deviceAuthorization.VerificationURI
+		}
+
+		// the prompt already shows the URL, so a browser failure is not fatal
+		_ = openBrowser(url)
+	}
+
+	created, err := pollSSOToken(ctx, client, token, authorization)
+	if err != nil {
+		return nil, err
+	}
+
+	token.AccessToken = aws.ToString(created.AccessToken)
+	token.ExpiresAt = time.Now().Add(time.Duration(created.ExpiresIn) * time.Second).UTC()
+	token.RefreshToken = aws.ToString(created.RefreshToken)
+
+	if err := token.Store(path, o.fileOptions); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func pollSSOToken(ctx context.Context, client SSOOIDCClient, token *credscacheutil.SSOToken, authorization *ssooidc.StartDeviceAuthorizationOutput) (*ssooidc.CreateTokenOutput, error) {
+	interval := time.Duration(authorization.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultSSOPollingInterval
+	}
+
+	for {
+		created, err := client.CreateToken(ctx, &ssooidc.CreateTokenInput{
+			ClientId:     aws.String(token.ClientID),
+			ClientSecret: aws.String(token.ClientSecret),
+			GrantType:    aws.String(ssoDeviceCodeGrantType),
+			DeviceCode:   authorization.DeviceCode,
+		})
+		if err == nil {
+			return created, nil
+		}
+
+		var authorizationPending *types.AuthorizationPendingException
+		var slowDown *types.SlowDownException
+		switch {
+		case errors.As(err, &authorizationPending):
+		case errors.As(err, &slowDown):
+			interval += ssoSlowDownIntervalPenalty
+		default:
+			err = fmt.Errorf("failed to create token, %w", err)
+			return nil, err
+		}
+
+		if err := sleepWithContext(ctx, interval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func ssoTokenCachePath(o *SSOLoginOptions) (string, error) {
+	dir := o.SSOTokenCacheDir
+	if dir == "" {
+		var err error
+		dir, err = credscacheutil.DefaultSSOTokenCacheDir()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	g := &credscacheutil.SSOTokenCacheKeyGenerator{
+		SessionName: o.SessionName,
+		StartURL:    o.StartURL,
+	}
+
+	key, err := g.CacheKey()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, fmt.Sprintf("%s.json", key)), nil
+}
+
+func (o *SSOLoginOptions) fileOptions(fo *credscacheutil.FileOptions) {
+	fo.InsecureSkipPermissionCheck = o.InsecureSkipPermissionCheck
+}
+
+func newWriterPrompt(w io.Writer) func(ctx context.Context, authorization *SSODeviceAuthorization) error {
+	return func(ctx context.Context, authorization *SSODeviceAuthorization) error {
+		_, err := fmt.Fprintf(w, "Attempting to automatically open the SSO authorization page in your default browser.\n"+
+			"If the browser does not open or you wish to use a different device to authorize this request, open the following URL:\n\n"+
+			"%s\n\nThen enter the code:\n\n%s\n", authorization.VerificationURI, authorization.UserCode)
+		return err
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSSOOIDCServer struct {
+	registerCalls int
+	tokenErrors   []string
+}
+
+func (s *fakeSSOOIDCServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.URL.Path {
+	case "/client/register":
+		s.registerCalls++
+		fmt.Fprint(w, `{"clientId":"ClientID","clientSecret":"ClientSecret","clientIdIssuedAt":1136214245,"clientSecretExpiresAt":1143990245}`)
+	case "/device_authorization":
+		fmt.Fprint(w, `{"deviceCode":"DeviceCode","userCode":"UserCode","verificationUri":"https://device.sso.us-east-1.amazonaws.com/","verificationUriComplete":"https://device.sso.us-east-1.amazonaws.com/?user_code=UserCode","expiresIn":600,"interval":1}`)
+	case "/token":
+		if len(s.tokenErrors) > 0 {
+			code := s.tokenErrors[0]
+			s.tokenErrors = s.tokenErrors[1:]
+			w.Header().Set("X-Amzn-ErrorType", code)
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error":"%s"}`, code)
+			return
+		}
+
+		fmt.Fprint(w, `{"accessToken":"AccessToken","expiresIn":3600,"refreshToken":"RefreshToken","tokenType":"Bearer"}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSSOLogin(t *testing.T) {
+	startURL := "https://d-123456789a.awsapps.com/start"
+	registrationExpiresAt := time.Date(2006, 4, 2, 15, 4, 5, 0, time.UTC)
+	cachedRegistrationExpiresAt := time.Now().UTC().Add(time.Duration(24) * time.Hour).Truncate(time.Second)
+
+	errPrompt := errors.New("failed to prompt")
+
+	type fields struct {
+		cachedToken *credscacheutil.SSOToken
+		tokenErrors []string
+		prompt      func(ctx context.Context, authorization *SSODeviceAuthorization) error
+	}
+
+	type args struct {
+		sessionName string
+		startURL    string
+	}
+
+	type expected struct {
+		token         *credscacheutil.SSOToken
+		registerCalls int
+		sleeps        []time.Duration
+		userCode      string
+		browserURL    string
+		err           error
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: new registration",
+			fields: fields{
+				cachedToken: nil,
+				tokenErrors: []string{"AuthorizationPendingException", "SlowDownException"},
+				prompt:      nil,
+			},
+			args: args{
+				sessionName: "my-sso",
+				startURL:    startURL,
+			},
+			expected: expected{
+				token: &credscacheutil.SSOToken{
+					AccessToken:           "AccessToken",
+					RefreshToken:          "RefreshToken",
+					ClientID:              "ClientID",
+					ClientSecret:          "ClientSecret",
+					RegistrationExpiresAt: &registrationExpiresAt,
+					Region:                "us-east-1",
+					StartURL:              startURL,
+				},
+				registerCalls: 1,
+				sleeps:        []time.Duration{time.Duration(1) * time.Second, time.Duration(6) * time.Second},
+				userCode:      "UserCode",
+				browserURL:    "https://device.sso.us-east-1.amazonaws.com/?user_code=UserCode",
+				err:           nil,
+			},
+		},
+		{
+			name: "positive case: cached registration",
+			fields: fields{
+				cachedToken: &credscacheutil.SSOToken{
+					AccessToken:           "ExpiredAccessToken",
+					ExpiresAt:             time.Now().UTC().Add(-time.Duration(15) * time.Minute),
+					ClientID:              "CachedClientID",
+					ClientSecret:          "CachedClientSecret",
+					RegistrationExpiresAt: &cachedRegistrationExpiresAt,
+				},
+				tokenErrors: []string{},
+				prompt:      nil,
+			},
+			args: args{
+				sessionName: "",
+				startURL:    startURL,
+			},
+			expected: expected{
+				token: &credscacheutil.SSOToken{
+					AccessToken:           "AccessToken",
+					RefreshToken:          "RefreshToken",
+					ClientID:              "CachedClientID",
+					ClientSecret:          "CachedClientSecret",
+					RegistrationExpiresAt: &cachedRegistrationExpiresAt,
+					Region:                "us-east-1",
+					StartURL:              startURL,
+				},
+				registerCalls: 0,
+				sleeps:        nil,
+				userCode:      "UserCode",
+				browserURL:    "https://device.sso.us-east-1.amazonaws.com/?user_code=UserCode",
+				err:           nil,
+			},
+		},
+		{
+			name: "negative case: prompt failure",
+			fields: fields{
+				cachedToken: nil,
+				tokenErrors: []string{},
+				prompt: func(ctx context.Context, authorization *SSODeviceAuthorization) error {
+					return errPrompt
+				},
+			},
+			args: args{
+				sessionName: "my-sso",
+				startURL:    startURL,
+			},
+			expected: expected{
+				err: errPrompt,
+			},
+		},
+		{
+			name: "negative case: sso config not found",
+			fields: fields{
+				cachedToken: nil,
+				tokenErrors: []string{},
+				prompt:      nil,
+			},
+			args: args{
+				sessionName: "",
+				startURL:    "",
+			},
+			expected: expected{
+				err: ErrSSOConfigNotFound,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tempDir := t.TempDir()
+			t.Setenv("AWS_CONFIG_FILE", filepath.Join(tempDir, "config"))
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(tempDir, "credentials"))
+			t.Setenv("AWS_PROFILE", "")
+			t.Setenv("AWS_DEFAULT_PROFILE", "")
+			os.WriteFile(filepath.Join(tempDir, "config"), []byte("[default]\nregion = us-east-1\n"), 0600)
+
+			g := &credscacheutil.SSOTokenCacheKeyGenerator{SessionName: tt.args.sessionName, StartURL: tt.args.startURL}
+			key, _ := g.CacheKey()
+			path := filepath.Join(tempDir, "sso", fmt.Sprintf("%s.json", key))
+			if tt.fields.cachedToken != nil {
+				tt.fields.cachedToken.Store(path)
+			}
+
+			server := &fakeSSOOIDCServer{tokenErrors: tt.fields.tokenErrors}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			var sleeps []time.Duration
+			defer func(fn func(ctx context.Context, d time.Duration) error) { sleepWithContext = fn }(sleepWithContext)
+			sleepWithContext = func(ctx context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			}
+
+			var userCode string
+			prompt := tt.fields.prompt
+			if prompt == nil {
+				prompt = func(ctx context.Context, authorization *SSODeviceAuthorization) error {
+					userCode = authorization.UserCode
+					return nil
+				}
+			}
+
+			var browserURL string
+
+			// Act
+			actual, err := SSOLogin(context.Background(), aws.Config{}, func(o *SSOLoginOptions) {
+				o.SessionName = tt.args.sessionName
+				o.StartURL = tt.args.startURL
+				o.Region = "us-east-1"
+				o.SSOTokenCacheDir = filepath.Join(tempDir, "sso")
+				o.Client = ssooidc.New(ssooidc.Options{
+					Region:           "us-east-1",
+					EndpointResolver: ssooidc.EndpointResolverFromURL(ts.URL),
+				})
+				o.Prompt = prompt
+				o.OpenBrowser = func(url string) error {
+					browserURL = url
+					return nil
+				}
+			})
+
+			// Assert
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+
+				assert.WithinDuration(t, time.Now().Add(time.Duration(1)*time.Hour), actual.ExpiresAt, time.Duration(1)*time.Minute)
+				actual.ExpiresAt = time.Time{}
+				assert.Equal(t, tt.expected.token, actual)
+
+				stored := new(credscacheutil.SSOToken)
+				assert.NoError(t, stored.Load(path))
+				assert.Equal(t, "AccessToken", stored.AccessToken)
+
+				assert.Equal(t, tt.expected.registerCalls, server.registerCalls)
+				assert.Equal(t, tt.expected.sleeps, sleeps)
+				assert.Equal(t, tt.expected.userCode, userCode)
+				assert.Equal(t, tt.expected.browserURL, browserURL)
+			} else {
+				var ssoLoginError *SSOLoginError
+				assert.ErrorAs(t, err, &ssoLoginError)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
@@ -25,7 +25,9 @@ import (
 	"unsafe"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 type CredentialsCacheUnsafeAccessor struct {
@@ -88,3 +90,64 @@ func (a *AssumeRoleProviderUnsafeAccessor) Options() stscreds.AssumeRoleOptions
 	ptr := a.options()
 	return *ptr
 }
+
+func (a *AssumeRoleProviderUnsafeAccessor) SetTokenProvider(tokenProvider func() (string, error)) {
+	ptr := a.options()
+	ptr.TokenProvider = tokenProvider
+}
+
+type ProcessProviderUnsafeAccessor struct {
+	ptr *processcreds.Provider
+}
+
+func NewProcessProviderUnsafeAccessor(ptr *processcreds.Provider) (*ProcessProviderUnsafeAccessor, error) {
+	if ptr == nil {
+		return nil, ErrNilPointer
+	}
+
+	a := &ProcessProviderUnsafeAccessor{
+		ptr: ptr,
+	}
+
+	return a, nil
+}
+
+func (a *ProcessProviderUnsafeAccessor) commandBuilder() *processcreds.NewCommandBuilder {
+	v := reflect.ValueOf(a.ptr).Elem()
+	f := v.FieldByName("commandBuilder")
+	ptr := (*processcreds.NewCommandBuilder)(unsafe.Pointer(f.UnsafeAddr()))
+	return ptr
+}
+
+func (a *ProcessProviderUnsafeAccessor) CommandBuilder() processcreds.NewCommandBuilder {
+	ptr := a.commandBuilder()
+	return *ptr
+}
+
+type STSClientUnsafeAccessor struct {
+	ptr *sts.Client
+}
+
+func NewSTSClientUnsafeAccessor(ptr *sts.Client) (*STSClientUnsafeAccessor, error) {
+	if ptr == nil {
+		return nil, ErrNilPointer
+	}
+
+	a := &STSClientUnsafeAccessor{
+		ptr: ptr,
+	}
+
+	return a, nil
+}
+
+func (a *STSClientUnsafeAccessor) options() *sts.Options {
+	v := reflect.ValueOf(a.ptr).Elem()
+	f := v.FieldByName("options")
+	ptr := (*sts.Options)(unsafe.Pointer(f.UnsafeAddr()))
+	return ptr
+}
+
+func (a *STSClientUnsafeAccessor) Options() sts.Options {
+	ptr := a.options()
+	return *ptr
+}
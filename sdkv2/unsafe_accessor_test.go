@@ -25,6 +25,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/stretchr/testify/assert"
@@ -256,3 +257,203 @@ func TestAssumeRoleProviderUnsafeAccessor_Options(t *testing.T) {
 		})
 	}
 }
+
+func TestAssumeRoleProviderUnsafeAccessor_SetTokenProvider(t *testing.T) {
+	tests := []struct {
+		name          string
+		accessor      *AssumeRoleProviderUnsafeAccessor
+		tokenProvider func() (string, error)
+		expected      string
+	}{
+		{
+			name:          "positive case: set token provider",
+			accessor:      &AssumeRoleProviderUnsafeAccessor{ptr: stscreds.NewAssumeRoleProvider(&sts.Client{}, "role_arn")},
+			tokenProvider: func() (string, error) { return "123456", nil },
+			expected:      "123456",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.accessor.SetTokenProvider(tt.tokenProvider)
+
+			actual, err := tt.accessor.Options().TokenProvider()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestNewProcessProviderUnsafeAccessor(t *testing.T) {
+	type args struct {
+		ptr *processcreds.Provider
+	}
+
+	type expected struct {
+		res *ProcessProviderUnsafeAccessor
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: Provider",
+			args: args{
+				ptr: &processcreds.Provider{},
+			},
+			expected: expected{
+				res: &ProcessProviderUnsafeAccessor{ptr: &processcreds.Provider{}},
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: nil Provider",
+			args: args{
+				ptr: nil,
+			},
+			expected: expected{
+				res: nil,
+				err: ErrNilPointer,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewProcessProviderUnsafeAccessor(tt.args.ptr)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
+
+func TestProcessProviderUnsafeAccessor_CommandBuilder(t *testing.T) {
+	type expected struct {
+		res processcreds.NewCommandBuilder
+	}
+
+	tests := []struct {
+		name     string
+		accessor *ProcessProviderUnsafeAccessor
+		expected expected
+	}{
+		{
+			name:     "positive case: get default command builder",
+			accessor: &ProcessProviderUnsafeAccessor{ptr: processcreds.NewProvider("/usr/local/bin/credential-process")},
+			expected: expected{
+				res: processcreds.DefaultNewCommandBuilder{Args: []string{"/usr/local/bin/credential-process"}},
+			},
+		},
+		{
+			name:     "positive case: get nil command builder",
+			accessor: &ProcessProviderUnsafeAccessor{ptr: &processcreds.Provider{}},
+			expected: expected{
+				res: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.accessor.CommandBuilder()
+
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
+
+func TestNewSTSClientUnsafeAccessor(t *testing.T) {
+	type args struct {
+		ptr *sts.Client
+	}
+
+	type expected struct {
+		res *STSClientUnsafeAccessor
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: Client",
+			args: args{
+				ptr: &sts.Client{},
+			},
+			expected: expected{
+				res: &STSClientUnsafeAccessor{ptr: &sts.Client{}},
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: nil Client",
+			args: args{
+				ptr: nil,
+			},
+			expected: expected{
+				res: nil,
+				err: ErrNilPointer,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewSTSClientUnsafeAccessor(tt.args.ptr)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}
+
+func TestSTSClientUnsafeAccessor_Options(t *testing.T) {
+	type expected struct {
+		region string
+	}
+
+	tests := []struct {
+		name     string
+		accessor *STSClientUnsafeAccessor
+		expected expected
+	}{
+		{
+			name:     "positive case: get region",
+			accessor: &STSClientUnsafeAccessor{ptr: sts.New(sts.Options{Region: "us-gov-west-1"})},
+			expected: expected{
+				region: "us-gov-west-1",
+			},
+		},
+		{
+			name:     "positive case: get empty option",
+			accessor: &STSClientUnsafeAccessor{ptr: &sts.Client{}},
+			expected: expected{
+				region: "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.accessor.Options()
+
+			assert.Equal(t, tt.expected.region, actual.Region)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package credscache
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Aton-Kish/aws-credscache-go/credscacheutil"
+	mock "github.com/Aton-Kish/aws-credscache-go/internal/mock/github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapProvider(t *testing.T) {
+	expiresIn15Minutes := time.Now().UTC().Add(time.Duration(15) * time.Minute).Truncate(time.Second)
+
+	type args struct {
+		keyer credscacheutil.CacheKeyer
+	}
+
+	type mockCredentialsProviderRetrieve struct {
+		times int
+		res   aws.Credentials
+		err   error
+	}
+
+	type expected struct {
+		res  aws.Credentials
+		path string
+		err  error
+	}
+
+	tests := []struct {
+		name                            string
+		args                            args
+		mockCredentialsProviderRetrieve mockCredentialsProviderRetrieve
+		expected                        expected
+	}{
+		{
+			name: "positive case: custom provider",
+			args: args{
+				keyer: credscacheutil.NewCacheKeyBuilder().AddString("Provider", "custom").AddString("Account", "123456789012"),
+			},
+			mockCredentialsProviderRetrieve: mockCredentialsProviderRetrieve{
+				times: 1,
+				res: aws.Credentials{
+					AccessKeyID:     "AccessKeyID",
+					SecretAccessKey: "SecretAccessKey",
+					SessionToken:    "SessionToken",
+					Source:          "TestProvider",
+					CanExpire:       true,
+					Expires:         expiresIn15Minutes,
+				},
+				err: nil,
+			},
+			expected: expected{
+				res: aws.Credentials{
+					AccessKeyID:     "AccessKeyID",
+					SecretAccessKey: "SecretAccessKey",
+					SessionToken:    "SessionToken",
+					Source:          "FileCacheProvider",
+					CanExpire:       true,
+					Expires:         expiresIn15Minutes,
+				},
+				path: "eb9091a389cd1f52325e932c1c22073cf9251825.json",
+				err:  nil,
+			},
+		},
+		{
+			name: "negative case: nil keyer",
+			args: args{
+				keyer: nil,
+			},
+			mockCredentialsProviderRetrieve: mockCredentialsProviderRetrieve{
+				times: 0,
+			},
+			expected: expected{
+				err: ErrNilPointer,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tempDir := t.TempDir()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCredentialsProvider := mock.NewMockCredentialsProvider(ctrl)
+			mockCredentialsProvider.
+				EXPECT().
+				Retrieve(gomock.Any()).
+				Return(tt.mockCredentialsProviderRetrieve.res, tt.mockCredentialsProviderRetrieve.err).
+				Times(tt.mockCredentialsProviderRetrieve.times)
+
+			// Act
+			credsCache, err := WrapProvider(mockCredentialsProvider, tt.args.keyer, func(o *FileCacheOptions) {
+				o.FileCacheDir = tempDir
+			})
+
+			// Assert
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+
+				actual, err := credsCache.Retrieve(context.Background())
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+				assert.FileExists(t, filepath.Join(tempDir, tt.expected.path))
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expected.err)
+			}
+		})
+	}
+}